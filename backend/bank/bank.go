@@ -0,0 +1,290 @@
+// Package bank keeps the local question bank: the questions served for types the Open Trivia
+// Database lacks and drawn on by the daily challenge, saved as a JSON file, with a history of
+// every change made to each question.
+package bank
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gclluch/TriviaApp-ReactGo/services"
+)
+
+var (
+	ErrNotFound        = errors.New("question not found")
+	ErrInvalidQuestion = errors.New("invalid question")
+)
+
+// Bank is a question bank saved to a JSON file in the format LoadQuestions reads. Each change
+// is also appended to a history file beside it.
+type Bank struct {
+	sync.Mutex
+	Path        string            // File the bank is saved to.
+	HistoryPath string            // File the revisions of its questions are appended to.
+	questions   []models.Question // Questions in the order they were added.
+	revisions   map[string]int    // Question ID to its latest revision number.
+	lastID      int               // Highest numeric ID ever given, so IDs of deleted questions are not reused.
+}
+
+// Open loads the bank saved at path, and its history from HistoryFile(path). A missing file
+// opens an empty bank, which is created on the first save.
+func Open(path string) (*Bank, error) {
+	questions, err := services.LoadQuestions(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	b := &Bank{
+		Path:        path,
+		HistoryPath: HistoryFile(path),
+		questions:   questions,
+		revisions:   make(map[string]int),
+	}
+
+	history, err := b.readHistory()
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range history {
+		b.revisions[revision.QuestionID] = revision.Number
+		b.noteID(revision.QuestionID)
+	}
+	for _, question := range questions {
+		b.noteID(question.ID)
+	}
+	return b, nil
+}
+
+// HistoryFile returns the history file kept beside a bank file: questions.json keeps its
+// history in questions.history.jsonl.
+func HistoryFile(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".history.jsonl"
+}
+
+// Questions returns the questions in the bank.
+func (b *Bank) Questions() []models.Question {
+	b.Lock()
+	defer b.Unlock()
+
+	return append([]models.Question{}, b.questions...)
+}
+
+// Get returns the question with the given ID.
+func (b *Bank) Get(id string) (models.Question, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if i := b.index(id); i >= 0 {
+		return b.questions[i], nil
+	}
+	return models.Question{}, ErrNotFound
+}
+
+// Add checks questions, numbers them after the questions already in the bank and saves them,
+// as a batch import does. If any question is unusable, none are added. It returns the
+// questions as added.
+func (b *Bank) Add(questions []models.Question) ([]models.Question, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	added := make([]models.Question, len(questions))
+	for i, question := range questions {
+		if err := services.NormalizeQuestion(&question); err != nil {
+			return nil, fmt.Errorf("%w: question %d: %v", ErrInvalidQuestion, i+1, err)
+		}
+		question.ID = strconv.Itoa(b.lastID + 1 + i)
+		added[i] = question
+	}
+
+	previous := b.questions
+	b.questions = append(append([]models.Question{}, b.questions...), added...)
+	if err := b.save(); err != nil {
+		b.questions = previous
+		return nil, err
+	}
+	for _, question := range added {
+		b.noteID(question.ID)
+	}
+	return added, b.record(ActionImported, added...)
+}
+
+// Create adds one question to the bank and returns it with its ID.
+func (b *Bank) Create(question models.Question) (models.Question, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if err := services.NormalizeQuestion(&question); err != nil {
+		return models.Question{}, fmt.Errorf("%w: %v", ErrInvalidQuestion, err)
+	}
+	question.ID = strconv.Itoa(b.lastID + 1)
+
+	b.questions = append(b.questions, question)
+	if err := b.save(); err != nil {
+		b.questions = b.questions[:len(b.questions)-1]
+		return models.Question{}, err
+	}
+	b.noteID(question.ID)
+	return question, b.record(ActionCreated, question)
+}
+
+// Update replaces the question with the given ID, keeping the ID.
+func (b *Bank) Update(id string, question models.Question) (models.Question, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	i := b.index(id)
+	if i < 0 {
+		return models.Question{}, ErrNotFound
+	}
+	if err := services.NormalizeQuestion(&question); err != nil {
+		return models.Question{}, fmt.Errorf("%w: %v", ErrInvalidQuestion, err)
+	}
+	question.ID = id
+	return question, b.replace(i, question, ActionUpdated)
+}
+
+// Tag adds tags to and removes tags from the question with the given ID.
+func (b *Bank) Tag(id string, add, remove []string) (models.Question, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	i := b.index(id)
+	if i < 0 {
+		return models.Question{}, ErrNotFound
+	}
+	removed := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removed[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+	question := b.questions[i]
+	var tags []string
+	for _, tag := range append(append([]string{}, question.Tags...), add...) {
+		if !removed[strings.ToLower(strings.TrimSpace(tag))] {
+			tags = append(tags, tag)
+		}
+	}
+	question.Tags = tags
+	if err := services.NormalizeQuestion(&question); err != nil {
+		return models.Question{}, fmt.Errorf("%w: %v", ErrInvalidQuestion, err)
+	}
+	return question, b.replace(i, question, ActionTagged)
+}
+
+// Delete retires the question with the given ID. Its history is kept.
+func (b *Bank) Delete(id string) error {
+	b.Lock()
+	defer b.Unlock()
+
+	i := b.index(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	question := b.questions[i]
+	previous := b.questions
+	b.questions = append(append([]models.Question{}, b.questions[:i]...), b.questions[i+1:]...)
+	if err := b.save(); err != nil {
+		b.questions = previous
+		return err
+	}
+	return b.record(ActionDeleted, question)
+}
+
+// Merge resolves a pair of duplicates: the question with removeID is deleted, and the one
+// with keepID gains its tags and, if it has none, its category and difficulty. Both changes
+// are saved together and recorded in the history.
+func (b *Bank) Merge(keepID, removeID string) (models.Question, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	keep, remove := b.index(keepID), b.index(removeID)
+	if keep < 0 || remove < 0 {
+		return models.Question{}, ErrNotFound
+	}
+	if keep == remove {
+		return models.Question{}, fmt.Errorf("%w: a question cannot be merged into itself", ErrInvalidQuestion)
+	}
+	kept, removed := b.questions[keep], b.questions[remove]
+	kept.Tags = append(append([]string{}, kept.Tags...), removed.Tags...)
+	if kept.Category == "" {
+		kept.Category = removed.Category
+	}
+	if kept.Difficulty == "" {
+		kept.Difficulty = removed.Difficulty
+	}
+	if err := services.NormalizeQuestion(&kept); err != nil {
+		return models.Question{}, fmt.Errorf("%w: %v", ErrInvalidQuestion, err)
+	}
+
+	previous := b.questions
+	questions := append([]models.Question{}, b.questions...)
+	questions[keep] = kept
+	b.questions = append(questions[:remove], questions[remove+1:]...)
+	if err := b.save(); err != nil {
+		b.questions = previous
+		return models.Question{}, err
+	}
+	if err := b.record(ActionUpdated, kept); err != nil {
+		return kept, err
+	}
+	return kept, b.recordMerge(ActionMerged, kept.ID, removed)
+}
+
+// replace saves a changed question in place and records the change. The caller must hold the lock.
+func (b *Bank) replace(i int, question models.Question, action Action) error {
+	previous := b.questions[i]
+	b.questions[i] = question
+	if err := b.save(); err != nil {
+		b.questions[i] = previous
+		return err
+	}
+	return b.record(action, question)
+}
+
+// index returns the position of the question with the given ID, or -1. The caller must hold the lock.
+func (b *Bank) index(id string) int {
+	for i, question := range b.questions {
+		if question.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// noteID keeps track of the highest numeric ID given. The caller must hold the lock.
+func (b *Bank) noteID(id string) {
+	if n, err := strconv.Atoi(id); err == nil && n > b.lastID {
+		b.lastID = n
+	}
+}
+
+// save writes the bank to its file, replacing the old file only once the new one is complete.
+// The caller must hold the lock.
+func (b *Bank) save() error {
+	bytes, err := json.MarshalIndent(b.questions, "", "  ")
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(b.Path), ".bank-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if err := temp.Chmod(0o644); err != nil {
+		temp.Close()
+		return err
+	}
+	if _, err := temp.Write(bytes); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), b.Path)
+}
@@ -0,0 +1,75 @@
+package bank
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestBankChangesAreSavedWithTheirHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "questions.json")
+	questionBank, err := Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open an empty bank: %v", err)
+	}
+
+	created, err := questionBank.Create(models.Question{
+		QuestionText: "Which pub name is most common in the UK?",
+		Options:      []string{"King's Head", "Red Lion"},
+		CorrectIndex: 1,
+		Category:     "Geography",
+		Difficulty:   "Easy",
+		Tags:         []string{"Pubs", "uk", "pubs"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create question: %v", err)
+	}
+	if created.ID != "1" || created.Difficulty != "easy" || len(created.Tags) != 2 {
+		t.Errorf("Expected a numbered question with normalized difficulty and tags; got %+v", created)
+	}
+	if _, err := questionBank.Create(models.Question{QuestionText: "No options"}); err == nil {
+		t.Errorf("Expected a question without options to be rejected")
+	}
+
+	created.CorrectIndex = 0
+	if _, err := questionBank.Update(created.ID, created); err != nil {
+		t.Fatalf("Failed to update question: %v", err)
+	}
+	if _, err := questionBank.Tag(created.ID, []string{"beer"}, []string{"UK"}); err != nil {
+		t.Fatalf("Failed to tag question: %v", err)
+	}
+	if found := questionBank.Search(Filter{Text: "pub name", Tag: "beer", Category: "geography"}); len(found) != 1 {
+		t.Errorf("Expected the question to be found; got %d results", len(found))
+	}
+	if found := questionBank.Search(Filter{Tag: "uk"}); len(found) != 0 {
+		t.Errorf("Expected the removed tag to no longer match; got %d results", len(found))
+	}
+	if err := questionBank.Delete(created.ID); err != nil {
+		t.Fatalf("Failed to delete question: %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen bank: %v", err)
+	}
+	if len(reopened.Questions()) != 0 {
+		t.Errorf("Expected the deletion to be saved; got %+v", reopened.Questions())
+	}
+	history, err := reopened.History(created.ID)
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	actions := []Action{ActionCreated, ActionUpdated, ActionTagged, ActionDeleted}
+	if len(history) != len(actions) {
+		t.Fatalf("Expected %d revisions; got %+v", len(actions), history)
+	}
+	for i, revision := range history {
+		if revision.Action != actions[i] || revision.Number != i+1 {
+			t.Errorf("Revision %d: got %s #%d; want %s #%d", i, revision.Action, revision.Number, actions[i], i+1)
+		}
+	}
+	if next, _ := reopened.Create(created); next.ID != "2" {
+		t.Errorf("Expected a deleted question's ID not to be reused; got %q", next.ID)
+	}
+}
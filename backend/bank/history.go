@@ -0,0 +1,106 @@
+package bank
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// Action is the kind of change a revision records.
+type Action string
+
+const (
+	ActionCreated  Action = "created"
+	ActionImported Action = "imported"
+	ActionUpdated  Action = "updated"
+	ActionTagged   Action = "tagged"
+	ActionDeleted  Action = "deleted"
+	ActionMerged   Action = "merged" // Deleted as a duplicate of another question.
+)
+
+// Revision is one change to a question.
+type Revision struct {
+	QuestionID string          `json:"questionId"`
+	Number     int             `json:"number"` // Counts up from 1 for each question.
+	Action     Action          `json:"action"`
+	At         time.Time       `json:"at"`
+	Question   models.Question `json:"question"`             // The question after the change; for deletions, as it was deleted.
+	MergedInto string          `json:"mergedInto,omitempty"` // For merges, the question kept in its place.
+}
+
+// History returns the revisions of the question with the given ID, oldest first. Deleted
+// questions keep their history; questions the bank file started with have none until changed.
+func (b *Bank) History(id string) ([]Revision, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if _, revised := b.revisions[id]; !revised && b.index(id) < 0 {
+		return nil, ErrNotFound
+	}
+	history, err := b.readHistory()
+	if err != nil {
+		return nil, err
+	}
+	var revisions []Revision
+	for _, revision := range history {
+		if revision.QuestionID == id {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions, nil
+}
+
+// record appends a revision for each question to the history file. The caller must hold the lock.
+func (b *Bank) record(action Action, questions ...models.Question) error {
+	return b.recordMerge(action, "", questions...)
+}
+
+// recordMerge is record noting the question the recorded ones were merged into, if any. The
+// caller must hold the lock.
+func (b *Bank) recordMerge(action Action, mergedInto string, questions ...models.Question) error {
+	file, err := os.OpenFile(b.HistoryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("saved, but failed to record history: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	now := time.Now().UTC()
+	for _, question := range questions {
+		b.revisions[question.ID]++
+		revision := Revision{QuestionID: question.ID, Number: b.revisions[question.ID], Action: action, At: now, Question: question, MergedInto: mergedInto}
+		if err := encoder.Encode(revision); err != nil {
+			return fmt.Errorf("saved, but failed to record history: %w", err)
+		}
+	}
+	return nil
+}
+
+// readHistory reads every revision in the history file, oldest first. The caller must hold the lock.
+func (b *Bank) readHistory() ([]Revision, error) {
+	file, err := os.Open(b.HistoryPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var history []Revision
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var revision Revision
+		if err := json.Unmarshal(scanner.Bytes(), &revision); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", b.HistoryPath, line, err)
+		}
+		history = append(history, revision)
+	}
+	return history, scanner.Err()
+}
@@ -0,0 +1,71 @@
+package bank
+
+import (
+	"strings"
+
+	"github.com/gclluch/TriviaApp-ReactGo/grading"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// Filter selects questions in a search. Zero values leave a filter off.
+type Filter struct {
+	Text       string // Words found in the question, its options or its accepted answers, ignoring case and accents.
+	Category   string // Category, ignoring case.
+	Difficulty string
+	Type       string
+	Source     string
+	Tag        string
+}
+
+// Search returns the questions matching every filter, in the order they were added.
+func (b *Bank) Search(filter Filter) []models.Question {
+	b.Lock()
+	defer b.Unlock()
+
+	words := strings.Fields(grading.Normalize(filter.Text))
+	var matching []models.Question
+	for _, question := range b.questions {
+		if filter.matches(question) && containsWords(question, words) {
+			matching = append(matching, question)
+		}
+	}
+	return matching
+}
+
+// matches reports whether a question passes every filter but the text.
+func (f Filter) matches(question models.Question) bool {
+	switch {
+	case f.Category != "" && !strings.EqualFold(f.Category, question.Category):
+		return false
+	case f.Difficulty != "" && !strings.EqualFold(f.Difficulty, question.Difficulty):
+		return false
+	case f.Type != "" && f.Type != question.QuestionType():
+		return false
+	case f.Source != "" && !strings.EqualFold(f.Source, question.Source):
+		return false
+	}
+	if f.Tag == "" {
+		return true
+	}
+	for _, tag := range question.Tags {
+		if strings.EqualFold(tag, f.Tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWords reports whether every word appears in the question's text, options or answers.
+func containsWords(question models.Question, words []string) bool {
+	if len(words) == 0 {
+		return true
+	}
+	parts := append([]string{question.QuestionText}, question.Options...)
+	text := grading.Normalize(strings.Join(append(parts, question.Answers...), " "))
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package bus
+
+import (
+	"sync"
+	"time"
+)
+
+// Handler is invoked for every message published on a subscribed topic.
+type Handler func(message []byte)
+
+// Bus delivers published messages to every subscriber of a topic, on every node sharing the bus.
+type Bus interface {
+	Publish(topic string, message []byte) error                              // Sends a message to all subscribers of topic.
+	Subscribe(topic string, handler Handler) (unsubscribe func(), err error) // Registers handler until unsubscribe is called.
+	Close() error                                                            // Releases the bus and its subscriptions.
+}
+
+// Locker coordinates exclusive, expiring ownership of keys across nodes.
+type Locker interface {
+	Acquire(key, owner string, ttl time.Duration) (bool, error) // Claims or renews key for owner; false if someone else holds it.
+	Release(key, owner string) error                            // Gives up key if owner still holds it.
+	Owner(key string) (string, error)                           // Returns the current holder of key, or "" if unowned.
+}
+
+// LocalBus is an in-process Bus for single-node deployments.
+type LocalBus struct {
+	sync.Mutex
+	nextID   int
+	handlers map[string]map[int]Handler
+}
+
+// NewLocalBus initializes an empty in-process bus.
+func NewLocalBus() *LocalBus {
+	return &LocalBus{handlers: make(map[string]map[int]Handler)}
+}
+
+// Publish synchronously calls every handler subscribed to topic.
+func (b *LocalBus) Publish(topic string, message []byte) error {
+	b.Lock()
+	handlers := make([]Handler, 0, len(b.handlers[topic]))
+	for _, handler := range b.handlers[topic] {
+		handlers = append(handlers, handler)
+	}
+	b.Unlock()
+
+	for _, handler := range handlers {
+		handler(message)
+	}
+	return nil
+}
+
+// Subscribe registers handler for topic.
+func (b *LocalBus) Subscribe(topic string, handler Handler) (func(), error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.handlers[topic] == nil {
+		b.handlers[topic] = make(map[int]Handler)
+	}
+	id := b.nextID
+	b.nextID++
+	b.handlers[topic][id] = handler
+
+	return func() {
+		b.Lock()
+		defer b.Unlock()
+
+		delete(b.handlers[topic], id)
+		if len(b.handlers[topic]) == 0 {
+			delete(b.handlers, topic)
+		}
+	}, nil
+}
+
+// Close drops every subscription.
+func (b *LocalBus) Close() error {
+	b.Lock()
+	defer b.Unlock()
+
+	b.handlers = make(map[string]map[int]Handler)
+	return nil
+}
+
+// LocalLocker is an in-process Locker for single-node deployments.
+type LocalLocker struct {
+	sync.Mutex
+	leases map[string]lease
+}
+
+// lease records who holds a key and until when.
+type lease struct {
+	owner   string
+	expires time.Time
+}
+
+// NewLocalLocker initializes an empty in-process locker.
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{leases: make(map[string]lease)}
+}
+
+// Acquire claims key for owner, or extends the lease if owner already holds it.
+func (l *LocalLocker) Acquire(key, owner string, ttl time.Duration) (bool, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	current, held := l.leases[key]
+	if held && current.owner != owner && time.Now().Before(current.expires) {
+		return false, nil
+	}
+	l.leases[key] = lease{owner: owner, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+// Release frees key if owner still holds it.
+func (l *LocalLocker) Release(key, owner string) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if current, held := l.leases[key]; held && current.owner == owner {
+		delete(l.leases, key)
+	}
+	return nil
+}
+
+// Owner reports the holder of an unexpired lease on key.
+func (l *LocalLocker) Owner(key string) (string, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	current, held := l.leases[key]
+	if !held || time.Now().After(current.expires) {
+		return "", nil
+	}
+	return current.owner, nil
+}
@@ -0,0 +1,106 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBus is a Bus backed by Redis pub/sub, shared by every node pointing at the same server.
+type RedisBus struct {
+	client *redis.Client
+}
+
+// NewRedisBus creates a bus publishing through the given Redis client.
+func NewRedisBus(client *redis.Client) *RedisBus {
+	return &RedisBus{client: client}
+}
+
+// Publish sends message to every node subscribed to topic.
+func (b *RedisBus) Publish(topic string, message []byte) error {
+	return b.client.Publish(context.Background(), topic, message).Err()
+}
+
+// Subscribe registers handler for topic. It returns once Redis has confirmed the
+// subscription, so messages published afterwards are not missed.
+func (b *RedisBus) Subscribe(topic string, handler Handler) (func(), error) {
+	ctx := context.Background()
+	pubsub := b.client.Subscribe(ctx, topic)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			handler([]byte(msg.Payload))
+		}
+	}()
+
+	return func() {
+		if err := pubsub.Close(); err != nil {
+			log.Printf("Failed to close subscription to %s: %v", topic, err)
+		}
+	}, nil
+}
+
+// Close shuts down the underlying client and all of its subscriptions.
+func (b *RedisBus) Close() error {
+	return b.client.Close()
+}
+
+// acquireScript claims a free key, or renews it when the caller already owns it.
+var acquireScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if current == false then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+if current == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+return 0`)
+
+// releaseScript deletes a key only when the caller still owns it.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisLocker is a Locker backed by expiring Redis keys.
+type RedisLocker struct {
+	client *redis.Client
+}
+
+// NewRedisLocker creates a locker storing leases through the given Redis client.
+func NewRedisLocker(client *redis.Client) *RedisLocker {
+	return &RedisLocker{client: client}
+}
+
+// Acquire claims key for owner, or extends the lease if owner already holds it.
+func (l *RedisLocker) Acquire(key, owner string, ttl time.Duration) (bool, error) {
+	acquired, err := acquireScript.Run(context.Background(), l.client, []string{key}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return acquired == 1, nil
+}
+
+// Release frees key if owner still holds it.
+func (l *RedisLocker) Release(key, owner string) error {
+	return releaseScript.Run(context.Background(), l.client, []string{key}, owner).Err()
+}
+
+// Owner reports the holder of key, or "" if nobody holds it.
+func (l *RedisLocker) Owner(key string) (string, error) {
+	owner, err := l.client.Get(context.Background(), key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return owner, err
+}
@@ -0,0 +1,74 @@
+package bus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestClient connects a fresh client to the stand-in Redis server, as a separate node would.
+func newTestClient(t *testing.T, server *miniredis.Miniredis) *redis.Client {
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestRedisBusDeliversAcrossNodes(t *testing.T) {
+	server := miniredis.RunT(t)
+	nodeA := NewRedisBus(newTestClient(t, server))
+	nodeB := NewRedisBus(newTestClient(t, server))
+
+	received := make(chan string, 1)
+	unsubscribe, err := nodeB.Subscribe("session:1:events", func(message []byte) {
+		received <- string(message)
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	defer unsubscribe()
+
+	if err := nodeA.Publish("session:1:events", []byte(`{"type":"sessionComplete"}`)); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+
+	select {
+	case message := <-received:
+		if message != `{"type":"sessionComplete"}` {
+			t.Errorf("Unexpected message: %s", message)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Message published on node A never reached node B")
+	}
+}
+
+func TestRedisLockerExclusiveOwnership(t *testing.T) {
+	server := miniredis.RunT(t)
+	nodeA := NewRedisLocker(newTestClient(t, server))
+	nodeB := NewRedisLocker(newTestClient(t, server))
+
+	if ok, err := nodeA.Acquire("session:1:owner", "a", time.Minute); err != nil || !ok {
+		t.Fatalf("Node A should acquire a free key; got %v, %v", ok, err)
+	}
+	if ok, _ := nodeB.Acquire("session:1:owner", "b", time.Minute); ok {
+		t.Fatalf("Node B should not acquire a key held by node A")
+	}
+	if owner, _ := nodeB.Owner("session:1:owner"); owner != "a" {
+		t.Errorf("Expected owner a; got %q", owner)
+	}
+
+	// Releasing a key someone else holds is a no-op.
+	if err := nodeB.Release("session:1:owner", "b"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if owner, _ := nodeA.Owner("session:1:owner"); owner != "a" {
+		t.Errorf("Node B must not release node A's lease")
+	}
+
+	// An expired lease can be claimed by another node.
+	server.FastForward(2 * time.Minute)
+	if ok, _ := nodeB.Acquire("session:1:owner", "b", time.Minute); !ok {
+		t.Fatalf("Node B should acquire an expired lease")
+	}
+}
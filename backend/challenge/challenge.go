@@ -0,0 +1,306 @@
+// Package challenge runs asynchronous head-to-head challenges: a player who finished a
+// single-player game sends a link, and the recipient plays the same questions later, under
+// the same options. Challenges can be saved to a file so links survive a restart.
+package challenge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/google/uuid"
+)
+
+// DefaultTTL is how long a challenge stays open when no TTL is configured.
+const DefaultTTL = 72 * time.Hour
+
+// Outcomes of a challenge once both players have finished.
+const (
+	OutcomeChallenger = "challenger"
+	OutcomeOpponent   = "opponent"
+	OutcomeDraw       = "draw"
+)
+
+var (
+	ErrNotFound        = errors.New("challenge not found")
+	ErrExpired         = errors.New("challenge has expired")
+	ErrAlreadyAccepted = errors.New("challenge was already accepted")
+)
+
+// Result is one side's performance in a challenge.
+type Result struct {
+	UserID     string    `json:"userId,omitempty"` // The server-issued user ID of the player, when known.
+	PlayerName string    `json:"playerName"`
+	Score      int       `json:"score"`
+	Finished   bool      `json:"finished"`
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+}
+
+// Challenge is a recorded game another player can replay.
+type Challenge struct {
+	ID         string             `json:"id"`
+	Questions  []models.Question  `json:"-"` // The challenger's questions, with their option order.
+	Options    models.GameOptions `json:"-"` // Options the challenger played with.
+	Challenger Result             `json:"challenger"`
+	Opponent   *Result            `json:"opponent"` // Nil until the challenge is accepted.
+	SessionID  string             `json:"-"`        // Session the opponent plays in.
+	Outcome    string             `json:"outcome"`  // Set once both players have finished.
+	CreatedAt  time.Time          `json:"createdAt"`
+	ExpiresAt  time.Time          `json:"expiresAt"`
+}
+
+// Challenges tracks open challenges.
+type Challenges struct {
+	sync.Mutex
+	TTL        time.Duration // How long a challenge stays open for its recipient.
+	Path       string        // File challenges are saved to; empty keeps them in memory.
+	challenges map[string]*Challenge
+	sessions   map[string]*Challenge // Opponent session ID to its challenge.
+}
+
+// NewChallenges initializes an empty set of challenges that expire after ttl.
+func NewChallenges(ttl time.Duration) *Challenges {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Challenges{
+		TTL:        ttl,
+		challenges: make(map[string]*Challenge),
+		sessions:   make(map[string]*Challenge),
+	}
+}
+
+// Create opens a challenge to replay the given questions, under the options the challenger
+// played with, against the challenger's result. Questions are copied and renumbered from 1,
+// in the order they were played.
+func (c *Challenges) Create(challenger Result, questions []models.Question, options models.GameOptions) Challenge {
+	c.Lock()
+	defer c.Unlock()
+
+	c.purgeExpired()
+
+	copied := make([]models.Question, len(questions))
+	for i, question := range questions {
+		question.Options = append([]string{}, question.Options...)
+		question.ID = fmt.Sprintf("%d", i+1)
+		copied[i] = question
+	}
+
+	now := time.Now()
+	challenge := &Challenge{
+		ID:         uuid.New().String(),
+		Questions:  copied,
+		Options:    options,
+		Challenger: challenger,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(c.TTL),
+	}
+	c.challenges[challenge.ID] = challenge
+	c.saveOrLog()
+	return *challenge
+}
+
+// Get returns a challenge. Challenges that were never completed expire after the TTL;
+// completed ones stay readable until they are purged.
+func (c *Challenges) Get(id string) (Challenge, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	challenge, err := c.get(id)
+	if err != nil {
+		return Challenge{}, err
+	}
+	return challenge.view(), nil
+}
+
+// get looks up a live challenge. The caller must hold the lock.
+func (c *Challenges) get(id string) (*Challenge, error) {
+	challenge, exists := c.challenges[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	if challenge.Outcome == "" && time.Now().After(challenge.ExpiresAt) {
+		return nil, ErrExpired
+	}
+	return challenge, nil
+}
+
+// Accept claims a challenge for its recipient, who can then play it once. If the game cannot
+// be set up afterwards, the claim should be given back with Cancel.
+func (c *Challenges) Accept(id, playerName string) (Challenge, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	challenge, err := c.get(id)
+	if err != nil {
+		return Challenge{}, err
+	}
+	if challenge.Opponent != nil {
+		return Challenge{}, ErrAlreadyAccepted
+	}
+	challenge.Opponent = &Result{PlayerName: playerName}
+	c.saveOrLog()
+	return challenge.view(), nil
+}
+
+// Cancel reopens a challenge whose recipient never got a game.
+func (c *Challenges) Cancel(id string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if challenge, exists := c.challenges[id]; exists && challenge.SessionID == "" {
+		challenge.Opponent = nil
+		c.saveOrLog()
+	}
+}
+
+// Attach links an accepted challenge to the session its recipient plays in.
+func (c *Challenges) Attach(id, sessionID string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if challenge, exists := c.challenges[id]; exists {
+		challenge.SessionID = sessionID
+		c.sessions[sessionID] = challenge
+		c.saveOrLog()
+	}
+}
+
+// RecordScore updates the opponent's result from the session they play in. Once they have
+// finished, the results are compared. Sessions that are not challenges are ignored.
+func (c *Challenges) RecordScore(sessionID string, score int, finished bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	challenge, exists := c.sessions[sessionID]
+	if !exists || challenge.Opponent.Finished {
+		return
+	}
+
+	challenge.Opponent.Score = score
+	defer c.saveOrLog()
+	if !finished {
+		return
+	}
+	challenge.Opponent.Finished = true
+	challenge.Opponent.FinishedAt = time.Now()
+
+	switch {
+	case challenge.Challenger.Score > score:
+		challenge.Outcome = OutcomeChallenger
+	case challenge.Challenger.Score < score:
+		challenge.Outcome = OutcomeOpponent
+	default:
+		challenge.Outcome = OutcomeDraw
+	}
+}
+
+// view returns a copy of the challenge that is safe to use without the lock. The caller must hold the lock.
+func (challenge *Challenge) view() Challenge {
+	view := *challenge
+	if challenge.Opponent != nil {
+		opponent := *challenge.Opponent
+		view.Opponent = &opponent
+	}
+	return view
+}
+
+// savedChallenge is a challenge as kept in the challenges file.
+type savedChallenge struct {
+	Challenge
+	Questions []models.Question  `json:"questions"`
+	Options   models.GameOptions `json:"options"`
+	SessionID string             `json:"sessionId,omitempty"`
+}
+
+// Load reads the challenges saved to Path. A missing file loads none.
+func (c *Challenges) Load() error {
+	if c.Path == "" {
+		return nil
+	}
+	bytes, err := os.ReadFile(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var saved []savedChallenge
+	if err := json.Unmarshal(bytes, &saved); err != nil {
+		return fmt.Errorf("%s: %w", c.Path, err)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	for _, entry := range saved {
+		challenge := entry.Challenge
+		challenge.Questions, challenge.Options, challenge.SessionID = entry.Questions, entry.Options, entry.SessionID
+		c.challenges[challenge.ID] = &challenge
+		if challenge.SessionID != "" {
+			c.sessions[challenge.SessionID] = &challenge
+		}
+	}
+	return nil
+}
+
+// saveOrLog saves the challenges, logging a failure; the challenges stay usable in memory.
+// The caller must hold the lock.
+func (c *Challenges) saveOrLog() {
+	if err := c.save(); err != nil {
+		log.Printf("Failed to save challenges: %v", err)
+	}
+}
+
+// save writes every challenge to Path, replacing the old file once the new one is complete.
+// The caller must hold the lock.
+func (c *Challenges) save() error {
+	if c.Path == "" {
+		return nil
+	}
+	saved := make([]savedChallenge, 0, len(c.challenges))
+	for _, challenge := range c.challenges {
+		saved = append(saved, savedChallenge{
+			Challenge: challenge.view(),
+			Questions: challenge.Questions,
+			Options:   challenge.Options,
+			SessionID: challenge.SessionID,
+		})
+	}
+	bytes, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(c.Path), ".challenges-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(bytes); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), c.Path)
+}
+
+// purgeExpired drops challenges past their expiry, completed or not. The caller must hold the lock.
+func (c *Challenges) purgeExpired() {
+	now := time.Now()
+	for id, challenge := range c.challenges {
+		if now.After(challenge.ExpiresAt) {
+			delete(c.challenges, id)
+			delete(c.sessions, challenge.SessionID)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package challenge
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestChallengeIsAcceptedOnceAndCompared(t *testing.T) {
+	challenges := NewChallenges(time.Hour)
+	questions := []models.Question{{ID: "1", Options: []string{"a", "b"}}, {ID: "4", Options: []string{"c", "d"}}}
+	created := challenges.Create(Result{PlayerName: "Ann", Score: 10, Finished: true}, questions, models.GameOptions{TimeLimit: 20})
+
+	if created.Questions[1].ID != "2" {
+		t.Errorf("Questions should be renumbered in play order; got %s", created.Questions[1].ID)
+	}
+	created.Questions[0].Options[0] = "changed"
+	if questions[0].Options[0] != "a" {
+		t.Errorf("Challenge questions should be copied")
+	}
+
+	if _, err := challenges.Accept(created.ID, "Bo"); err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	if _, err := challenges.Accept(created.ID, "Cy"); err != ErrAlreadyAccepted {
+		t.Errorf("Expected ErrAlreadyAccepted; got %v", err)
+	}
+	challenges.Attach(created.ID, "session")
+
+	challenges.RecordScore("session", 20, false)
+	if found, _ := challenges.Get(created.ID); found.Outcome != "" || found.Opponent.Score != 20 {
+		t.Errorf("Results should not be compared before the opponent finishes: %+v", found)
+	}
+	challenges.RecordScore("session", 20, true)
+	if found, _ := challenges.Get(created.ID); found.Outcome != OutcomeOpponent {
+		t.Errorf("Expected the opponent to win; got %q", found.Outcome)
+	}
+}
+
+func TestChallengeExpires(t *testing.T) {
+	challenges := NewChallenges(time.Millisecond)
+	created := challenges.Create(Result{PlayerName: "Ann"}, nil, models.GameOptions{})
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := challenges.Accept(created.ID, "Bo"); err != ErrExpired {
+		t.Errorf("Expected ErrExpired; got %v", err)
+	}
+	challenges.Create(Result{PlayerName: "Ann"}, nil, models.GameOptions{})
+	if _, err := challenges.Get(created.ID); err != ErrNotFound {
+		t.Errorf("Expired challenges should be purged; got %v", err)
+	}
+}
+
+func TestChallengesSurviveARestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "challenges.json")
+	challenges := NewChallenges(time.Hour)
+	challenges.Path = path
+	questions := []models.Question{{ID: "1", Options: []string{"a", "b"}, CorrectIndex: 1}}
+	options := models.GameOptions{TimeLimit: 15, QuestionType: models.QuestionMultiple}
+	created := challenges.Create(Result{PlayerName: "Ann", Score: 10, Finished: true}, questions, options)
+
+	restarted := NewChallenges(time.Hour)
+	restarted.Path = path
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	accepted, err := restarted.Accept(created.ID, "Bo")
+	if err != nil {
+		t.Fatalf("Accept after a restart failed: %v", err)
+	}
+	if accepted.Options.TimeLimit != 15 || len(accepted.Questions) != 1 || accepted.Questions[0].CorrectIndex != 1 {
+		t.Errorf("Expected the challenge to keep its questions and options; got %+v", accepted)
+	}
+	if accepted.Challenger.Score != 10 {
+		t.Errorf("Expected the challenger's result to be kept; got %+v", accepted.Challenger)
+	}
+}
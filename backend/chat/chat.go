@@ -0,0 +1,231 @@
+package chat
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxMessageLength is the longest chat message accepted, in characters.
+const MaxMessageLength = 280
+
+var (
+	ErrChatDisabled    = errors.New("chat is disabled")
+	ErrMuted           = errors.New("player is muted")
+	ErrRateLimited     = errors.New("sending too fast")
+	ErrEmptyMessage    = errors.New("message is empty")
+	ErrMessageTooLong  = errors.New("message is too long")
+	ErrRejected        = errors.New("message rejected by moderation")
+	ErrUnknownReaction = errors.New("unknown reaction")
+)
+
+// Reactions lists the quick reactions players can send.
+var Reactions = map[string]bool{"👍": true, "👏": true, "😂": true, "😮": true, "🎉": true, "🔥": true, "😢": true}
+
+// Message is a single chat message kept in a room's history.
+type Message struct {
+	ID         string    `json:"id"`
+	PlayerID   string    `json:"playerId"`
+	PlayerName string    `json:"playerName"`
+	Text       string    `json:"text"`
+	SentAt     time.Time `json:"sentAt"`
+}
+
+// Moderator inspects chat text before it is published. It returns the text to publish,
+// possibly altered, or ErrRejected to drop the message.
+type Moderator interface {
+	Moderate(playerID, text string) (string, error)
+}
+
+// ModeratorFunc adapts a function to the Moderator interface.
+type ModeratorFunc func(playerID, text string) (string, error)
+
+// Moderate calls f.
+func (f ModeratorFunc) Moderate(playerID, text string) (string, error) {
+	return f(playerID, text)
+}
+
+// WordFilter masks blocked words with asterisks. Only whole words are matched, so
+// innocent words that merely contain a blocked one are left alone.
+type WordFilter struct {
+	Words []string // Blocked words, matched case-insensitively.
+}
+
+// Moderate replaces every blocked word with asterisks of the same length.
+func (f WordFilter) Moderate(playerID, text string) (string, error) {
+	blocked := make(map[string]bool, len(f.Words))
+	for _, word := range f.Words {
+		blocked[strings.ToLower(strings.TrimSpace(word))] = true
+	}
+
+	var filtered strings.Builder
+	word := []rune{}
+	flush := func() {
+		if blocked[strings.ToLower(string(word))] {
+			filtered.WriteString(strings.Repeat("*", len(word)))
+		} else {
+			filtered.WriteString(string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		filtered.WriteRune(r)
+	}
+	flush()
+	return filtered.String(), nil
+}
+
+// Config controls the limits applied to a chat room.
+type Config struct {
+	HistorySize int           // Messages kept for players joining late.
+	RateLimit   int           // Messages and reactions a player may send per RateWindow.
+	RateWindow  time.Duration // Sliding window for RateLimit.
+	Moderator   Moderator     // Optional filter applied to every message.
+}
+
+// DefaultConfig returns the limits used when none are configured.
+func DefaultConfig() Config {
+	return Config{HistorySize: 50, RateLimit: 5, RateWindow: 10 * time.Second}
+}
+
+// Room holds the chat state of a single game session.
+type Room struct {
+	sync.Mutex
+	config  Config
+	enabled bool
+	muted   map[string]bool
+	history []Message
+	sent    map[string][]time.Time // Recent send times per player, for rate limiting.
+}
+
+// NewRoom creates an enabled chat room.
+func NewRoom(config Config) *Room {
+	return &Room{
+		config:  config,
+		enabled: true,
+		muted:   make(map[string]bool),
+		sent:    make(map[string][]time.Time),
+	}
+}
+
+// Post validates, moderates and records a chat message.
+func (r *Room) Post(playerID, playerName, text string) (Message, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Message{}, ErrEmptyMessage
+	}
+	if utf8.RuneCountInString(text) > MaxMessageLength {
+		return Message{}, ErrMessageTooLong
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.admit(playerID); err != nil {
+		return Message{}, err
+	}
+
+	if r.config.Moderator != nil {
+		moderated, err := r.config.Moderator.Moderate(playerID, text)
+		if err != nil {
+			return Message{}, err
+		}
+		text = moderated
+	}
+
+	message := Message{
+		ID:         uuid.New().String(),
+		PlayerID:   playerID,
+		PlayerName: playerName,
+		Text:       text,
+		SentAt:     time.Now(),
+	}
+	r.history = append(r.history, message)
+	if overflow := len(r.history) - r.config.HistorySize; overflow > 0 {
+		r.history = append([]Message(nil), r.history[overflow:]...)
+	}
+	return message, nil
+}
+
+// React validates a quick reaction. Reactions share the chat rate limit but are not kept in history.
+func (r *Room) React(playerID, reaction string) error {
+	if !Reactions[reaction] {
+		return ErrUnknownReaction
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	return r.admit(playerID)
+}
+
+// admit checks the room state and the player's rate limit, recording the attempt if allowed.
+// The caller must hold the lock.
+func (r *Room) admit(playerID string) error {
+	if !r.enabled {
+		return ErrChatDisabled
+	}
+	if r.muted[playerID] {
+		return ErrMuted
+	}
+
+	now := time.Now()
+	recent := r.sent[playerID][:0]
+	for _, at := range r.sent[playerID] {
+		if now.Sub(at) < r.config.RateWindow {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= r.config.RateLimit {
+		r.sent[playerID] = recent
+		return ErrRateLimited
+	}
+	r.sent[playerID] = append(recent, now)
+	return nil
+}
+
+// History returns a copy of the retained messages, oldest first.
+func (r *Room) History() []Message {
+	r.Lock()
+	defer r.Unlock()
+
+	return append([]Message{}, r.history...)
+}
+
+// SetEnabled turns chat and reactions on or off for everyone.
+func (r *Room) SetEnabled(enabled bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.enabled = enabled
+}
+
+// Enabled reports whether chat is on.
+func (r *Room) Enabled() bool {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.enabled
+}
+
+// SetMuted silences or restores a single player.
+func (r *Room) SetMuted(playerID string, muted bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	if muted {
+		r.muted[playerID] = true
+	} else {
+		delete(r.muted, playerID)
+	}
+}
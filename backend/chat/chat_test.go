@@ -0,0 +1,61 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRoomKeepsBoundedHistory(t *testing.T) {
+	room := NewRoom(Config{HistorySize: 3, RateLimit: 100, RateWindow: time.Minute})
+	for i := 0; i < 5; i++ {
+		if _, err := room.Post("p1", "Player 1", fmt.Sprintf("message %d", i)); err != nil {
+			t.Fatalf("Post failed: %v", err)
+		}
+	}
+
+	history := room.History()
+	if len(history) != 3 || history[0].Text != "message 2" || history[2].Text != "message 4" {
+		t.Errorf("Expected the three most recent messages; got %+v", history)
+	}
+}
+
+func TestRoomRateLimitsPerPlayer(t *testing.T) {
+	room := NewRoom(Config{HistorySize: 10, RateLimit: 2, RateWindow: time.Minute})
+	room.Post("p1", "Player 1", "one")
+	room.React("p1", "👍")
+
+	if _, err := room.Post("p1", "Player 1", "three"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited; got %v", err)
+	}
+	if _, err := room.Post("p2", "Player 2", "hello"); err != nil {
+		t.Errorf("Other players should not be limited; got %v", err)
+	}
+}
+
+func TestRoomHostControls(t *testing.T) {
+	room := NewRoom(DefaultConfig())
+
+	room.SetMuted("p1", true)
+	if _, err := room.Post("p1", "Player 1", "hi"); !errors.Is(err, ErrMuted) {
+		t.Errorf("Expected ErrMuted; got %v", err)
+	}
+
+	room.SetEnabled(false)
+	if err := room.React("p2", "🎉"); !errors.Is(err, ErrChatDisabled) {
+		t.Errorf("Expected ErrChatDisabled; got %v", err)
+	}
+}
+
+func TestWordFilterMasksWholeWords(t *testing.T) {
+	room := NewRoom(Config{HistorySize: 10, RateLimit: 10, RateWindow: time.Minute, Moderator: WordFilter{Words: []string{"darn"}}})
+
+	message, err := room.Post("p1", "Player 1", "Darn it, darning socks!")
+	if err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	if message.Text != "**** it, darning socks!" {
+		t.Errorf("Unexpected moderated text: %q", message.Text)
+	}
+}
@@ -0,0 +1,270 @@
+// Package daily runs the daily challenge: one question set per day, built deterministically
+// from a seed so every player answers the same questions in the same order, with one
+// attempt per player and a leaderboard per day. Players are known by their server-issued
+// user ID, and attempts are saved to a file so a restart does not grant new ones.
+package daily
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gclluch/TriviaApp-ReactGo/services"
+)
+
+// DateFormat is the layout of challenge dates, which follow the UTC calendar.
+const DateFormat = "2006-01-02"
+
+// MinBankRatio is how many times the daily set size the bank should hold, so consecutive
+// days draw mostly different questions.
+const MinBankRatio = 3
+
+var (
+	ErrUnavailable      = errors.New("daily challenge is not available")
+	ErrAlreadyAttempted = errors.New("player already attempted today's challenge")
+	ErrMissingPlayer    = errors.New("userToken is required")
+	ErrSmallBank        = errors.New("question bank is too small for varied daily sets")
+)
+
+// Config controls how daily question sets are built.
+type Config struct {
+	Bank   []models.Question // Questions the daily sets are drawn from; only single-answer ones are used.
+	Size   int               // Questions per daily set, capped at the bank size.
+	Secret string            // Mixed into each day's seed so upcoming sets cannot be predicted.
+	Path   string            // File attempts are saved to; empty keeps them in memory.
+}
+
+// Attempt is a player's run at one day's challenge.
+type Attempt struct {
+	PlayerID   string    `json:"playerId"` // The player's user ID.
+	PlayerName string    `json:"playerName"`
+	SessionID  string    `json:"-"`
+	Score      int       `json:"score"`
+	StartedAt  time.Time `json:"startedAt"`
+	ScoredAt   time.Time `json:"-"` // When the score last went up; earlier wins a tie.
+	Rank       int       `json:"rank"`
+}
+
+// Challenges tracks daily challenge attempts.
+type Challenges struct {
+	sync.Mutex
+	Config
+	attempts map[string]map[string]*Attempt // Date to player ID to attempt.
+	sessions map[string]*Attempt            // Session ID to the attempt played in it.
+}
+
+// NewChallenges initializes daily challenges drawn from the configured bank. The daily game
+// is answered by picking an option, so only multiple choice and true/false questions are drawn.
+func NewChallenges(config Config) *Challenges {
+	var bank []models.Question
+	for _, question := range config.Bank {
+		if question.SingleAnswer() {
+			bank = append(bank, question)
+		}
+	}
+	config.Bank = bank
+	return &Challenges{
+		Config:   config,
+		attempts: make(map[string]map[string]*Attempt),
+		sessions: make(map[string]*Attempt),
+	}
+}
+
+// CheckBank reports ErrSmallBank when the bank holds fewer than MinBankRatio times the
+// daily set size, in which case every day asks nearly the same questions.
+func (c *Challenges) CheckBank() error {
+	if c.Size > 0 && len(c.Bank) < c.Size*MinBankRatio {
+		return fmt.Errorf("%w: %d single-answer questions for sets of %d", ErrSmallBank, len(c.Bank), c.Size)
+	}
+	return nil
+}
+
+// savedAttempt is an attempt as kept in the attempts file.
+type savedAttempt struct {
+	Date string `json:"date"`
+	Attempt
+	ScoredAt time.Time `json:"scoredAt"`
+}
+
+// Load reads the attempts saved to the configured file. A missing file loads none.
+func (c *Challenges) Load() error {
+	if c.Path == "" {
+		return nil
+	}
+	bytes, err := os.ReadFile(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var saved []savedAttempt
+	if err := json.Unmarshal(bytes, &saved); err != nil {
+		return fmt.Errorf("%s: %w", c.Path, err)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	for _, entry := range saved {
+		attempt := entry.Attempt
+		attempt.ScoredAt = entry.ScoredAt
+		if c.attempts[entry.Date] == nil {
+			c.attempts[entry.Date] = make(map[string]*Attempt)
+		}
+		c.attempts[entry.Date][attempt.PlayerID] = &attempt
+	}
+	return nil
+}
+
+// save writes every attempt to the configured file, replacing the old file once the new one
+// is complete. The caller must hold the lock.
+func (c *Challenges) save() error {
+	if c.Path == "" {
+		return nil
+	}
+	var saved []savedAttempt
+	for date, day := range c.attempts {
+		for _, attempt := range day {
+			saved = append(saved, savedAttempt{Date: date, Attempt: *attempt, ScoredAt: attempt.ScoredAt})
+		}
+	}
+	bytes, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(c.Path), ".daily-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(bytes); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), c.Path)
+}
+
+// Today returns the date of the current challenge.
+func Today() string {
+	return time.Now().UTC().Format(DateFormat)
+}
+
+// Seed derives the seed for a date's question set.
+func (c *Challenges) Seed(date string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(c.Secret + "|" + date))
+	return int64(hash.Sum64())
+}
+
+// Questions builds the question set for a date. The same date always yields the same set.
+func (c *Challenges) Questions(date string) ([]models.Question, error) {
+	if len(c.Bank) == 0 || c.Size <= 0 {
+		return nil, ErrUnavailable
+	}
+	return services.SeededQuestions(c.Bank, c.Size, c.Seed(date)), nil
+}
+
+// Start claims a player's one attempt at a date's challenge, saving the claim before it is
+// granted. The player ID must be a user ID the server issued and verified. If the game
+// cannot be set up afterwards, the claim should be given back with Cancel.
+func (c *Challenges) Start(date, playerID, playerName string) (*Attempt, error) {
+	if playerID == "" {
+		return nil, ErrMissingPlayer
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	day, exists := c.attempts[date]
+	if !exists {
+		day = make(map[string]*Attempt)
+		c.attempts[date] = day
+	}
+	if _, attempted := day[playerID]; attempted {
+		return nil, ErrAlreadyAttempted
+	}
+
+	attempt := &Attempt{PlayerID: playerID, PlayerName: playerName, StartedAt: time.Now()}
+	day[playerID] = attempt
+	if err := c.save(); err != nil {
+		delete(day, playerID)
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// Cancel gives back an attempt that never got a game.
+func (c *Challenges) Cancel(date string, attempt *Attempt) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.attempts[date][attempt.PlayerID] == attempt {
+		delete(c.attempts[date], attempt.PlayerID)
+		if err := c.save(); err != nil {
+			log.Printf("Failed to save daily challenge attempts: %v", err)
+		}
+	}
+}
+
+// Attach links an attempt to the session it is played in.
+func (c *Challenges) Attach(attempt *Attempt, sessionID string) {
+	c.Lock()
+	defer c.Unlock()
+
+	attempt.SessionID = sessionID
+	c.sessions[sessionID] = attempt
+}
+
+// RecordScore updates the attempt played in a session. Sessions that are not daily
+// challenge attempts are ignored.
+func (c *Challenges) RecordScore(sessionID string, score int) {
+	c.Lock()
+	defer c.Unlock()
+
+	if attempt, exists := c.sessions[sessionID]; exists && score > attempt.Score {
+		attempt.Score = score
+		attempt.ScoredAt = time.Now()
+		if err := c.save(); err != nil {
+			log.Printf("Failed to save daily challenge attempts: %v", err)
+		}
+	}
+}
+
+// Leaderboard ranks a date's attempts by score; ties go to whoever reached the score first.
+func (c *Challenges) Leaderboard(date string) []Attempt {
+	c.Lock()
+	defer c.Unlock()
+
+	board := make([]Attempt, 0, len(c.attempts[date]))
+	for _, attempt := range c.attempts[date] {
+		board = append(board, *attempt)
+	}
+	sort.Slice(board, func(i, j int) bool {
+		if board[i].Score != board[j].Score {
+			return board[i].Score > board[j].Score
+		}
+		return board[i].ScoredAt.Before(board[j].ScoredAt)
+	})
+	for i := range board {
+		if i > 0 && board[i-1].Score == board[i].Score && board[i-1].ScoredAt.Equal(board[i].ScoredAt) {
+			board[i].Rank = board[i-1].Rank
+		} else {
+			board[i].Rank = i + 1
+		}
+	}
+	return board
+}
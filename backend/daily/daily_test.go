@@ -0,0 +1,117 @@
+package daily
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func testBank() []models.Question {
+	var bank []models.Question
+	for _, text := range []string{"A", "B", "C", "D", "E", "F"} {
+		bank = append(bank, models.Question{
+			QuestionText: text,
+			Options:      []string{text + "1", text + "2", text + "3", text + "4"},
+			CorrectIndex: 0,
+		})
+	}
+	return bank
+}
+
+func TestQuestionsAreDeterministicPerDate(t *testing.T) {
+	challenges := NewChallenges(Config{Bank: testBank(), Size: 4, Secret: "s"})
+
+	first, err := challenges.Questions("2026-10-19")
+	if err != nil {
+		t.Fatalf("Questions failed: %v", err)
+	}
+	again, _ := NewChallenges(Config{Bank: testBank(), Size: 4, Secret: "s"}).Questions("2026-10-19")
+	if !reflect.DeepEqual(first, again) {
+		t.Errorf("The same date and secret should build the same set")
+	}
+	if len(first) != 4 || first[0].ID != "1" {
+		t.Errorf("Unexpected set: %+v", first)
+	}
+	for _, question := range first {
+		if question.Options[question.CorrectIndex] != question.QuestionText+"1" {
+			t.Errorf("Correct index lost track of the answer in %+v", question)
+		}
+	}
+
+	other, _ := challenges.Questions("2026-10-20")
+	if reflect.DeepEqual(first, other) {
+		t.Errorf("Different dates should build different sets")
+	}
+
+	mixed := append(testBank(), models.Question{Type: models.QuestionFreeText, QuestionText: "Typed", Answers: []string{"typed"}})
+	all, _ := NewChallenges(Config{Bank: mixed, Size: len(mixed)}).Questions("2026-10-19")
+	for _, question := range all {
+		if !question.SingleAnswer() {
+			t.Errorf("Daily sets should only draw single-answer questions; got %+v", question)
+		}
+	}
+	if len(all) != len(testBank()) {
+		t.Errorf("Expected every single-answer question to be drawn; got %d", len(all))
+	}
+}
+
+func TestOneAttemptPerPlayerPerDay(t *testing.T) {
+	challenges := NewChallenges(Config{Bank: testBank(), Size: 4})
+
+	first, err := challenges.Start("2026-10-19", "p1", "Ann")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if _, err := challenges.Start("2026-10-19", "p1", "Ann"); err != ErrAlreadyAttempted {
+		t.Errorf("Expected ErrAlreadyAttempted; got %v", err)
+	}
+	if _, err := challenges.Start("2026-10-20", "p1", "Ann"); err != nil {
+		t.Errorf("A new day should allow a new attempt; got %v", err)
+	}
+
+	second, _ := challenges.Start("2026-10-19", "p2", "Bo")
+	challenges.Attach(first, "s1")
+	challenges.Attach(second, "s2")
+	challenges.RecordScore("s2", 20)
+	challenges.RecordScore("s1", 10)
+	challenges.RecordScore("unrelated", 50)
+
+	board := challenges.Leaderboard("2026-10-19")
+	if len(board) != 2 || board[0].PlayerID != "p2" || board[0].Rank != 1 || board[1].Score != 10 {
+		t.Errorf("Unexpected leaderboard: %+v", board)
+	}
+}
+
+func TestAttemptsSurviveARestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attempts.json")
+	challenges := NewChallenges(Config{Bank: testBank(), Size: 4, Path: path})
+	attempt, err := challenges.Start("2026-10-19", "p1", "Ann")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	challenges.Attach(attempt, "s1")
+	challenges.RecordScore("s1", 30)
+
+	restarted := NewChallenges(Config{Bank: testBank(), Size: 4, Path: path})
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, err := restarted.Start("2026-10-19", "p1", "Ann"); err != ErrAlreadyAttempted {
+		t.Errorf("Expected the saved attempt to count after a restart; got %v", err)
+	}
+	if board := restarted.Leaderboard("2026-10-19"); len(board) != 1 || board[0].Score != 30 {
+		t.Errorf("Unexpected leaderboard after a restart: %+v", board)
+	}
+}
+
+func TestSmallBanksAreReported(t *testing.T) {
+	if err := NewChallenges(Config{Bank: testBank(), Size: 4}).CheckBank(); !errors.Is(err, ErrSmallBank) {
+		t.Errorf("Expected a bank of 6 to be too small for sets of 4; got %v", err)
+	}
+	if err := NewChallenges(Config{Bank: testBank(), Size: 2}).CheckBank(); err != nil {
+		t.Errorf("Expected a bank of 6 to be enough for sets of 2; got %v", err)
+	}
+}
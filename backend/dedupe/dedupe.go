@@ -0,0 +1,223 @@
+// Package dedupe finds questions asked more than once: word for word, or in slightly different
+// words with the same answer, as happens when banks from different sources are merged.
+// Wording is compared by the simhash of the question text, which changes little when the
+// text changes little.
+package dedupe
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gclluch/TriviaApp-ReactGo/grading"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// DefaultThreshold is how many of the 64 simhash bits two questions' wordings may differ in
+// and still count as the same question, if their answers match too.
+const DefaultThreshold = 12
+
+// Simhash returns the 64-bit simhash of a text: each word votes on every bit through its own
+// hash, and the majority sets the bit. Texts sharing most of their words share most bits.
+func Simhash(text string) uint64 {
+	words := strings.Fields(grading.Normalize(text))
+	var votes [64]int
+	vote := func(feature string) {
+		hash := fnv.New64a()
+		hash.Write([]byte(feature))
+		sum := hash.Sum64()
+		for bit := range votes {
+			if sum&(1<<bit) != 0 {
+				votes[bit]++
+			} else {
+				votes[bit]--
+			}
+		}
+	}
+	for _, word := range words {
+		vote(word)
+	}
+
+	var simhash uint64
+	for bit, count := range votes {
+		if count > 0 {
+			simhash |= 1 << bit
+		}
+	}
+	return simhash
+}
+
+// Distance returns how many bits two simhashes differ in.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Key returns a stable identity for a question: the same for the question however often it
+// is fetched, whatever ID it is given and however its options are shuffled.
+func Key(question models.Question) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(grading.Normalize(question.QuestionText)))
+	hash.Write([]byte{0})
+	hash.Write([]byte(AnswerKey(question)))
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
+
+// AnswerKey returns a question's correct answer in a form comparable across questions that
+// word or order their options differently.
+func AnswerKey(question models.Question) string {
+	switch question.QuestionType() {
+	case models.QuestionFreeText:
+		if len(question.Answers) > 0 {
+			return grading.Normalize(question.Answers[0])
+		}
+	case models.QuestionNumeric:
+		return strconv.FormatFloat(question.NumericValue(), 'g', -1, 64)
+	case models.QuestionMultiSelect:
+		correct := make([]string, 0, len(question.CorrectIndexes))
+		for _, index := range question.CorrectIndexes {
+			if question.HasOption(index) {
+				correct = append(correct, grading.Normalize(question.Options[index]))
+			}
+		}
+		sort.Strings(correct)
+		return strings.Join(correct, "|")
+	case models.QuestionOrdering:
+		order := make([]string, 0, len(question.CorrectOrder))
+		for _, index := range question.CorrectOrder {
+			if question.HasOption(index) {
+				order = append(order, grading.Normalize(question.Options[index]))
+			}
+		}
+		return strings.Join(order, ">")
+	default:
+		if question.HasOption(question.CorrectIndex) {
+			return grading.Normalize(question.Options[question.CorrectIndex])
+		}
+	}
+	return ""
+}
+
+// Match is a question found to repeat an earlier one.
+type Match struct {
+	Position int  `json:"-"`        // Position of the earlier question among those added to the index.
+	Exact    bool `json:"exact"`    // The texts and answers are the same once normalized; otherwise they are worded alike and share an answer.
+	Distance int  `json:"distance"` // Simhash bits the wordings differ in.
+}
+
+// entry is a question in an index, reduced to what is compared.
+type entry struct {
+	text   string
+	answer string
+	hash   uint64
+}
+
+// Index holds questions to check new ones against.
+type Index struct {
+	Threshold int // Simhash bits similar wordings may differ in.
+	entries   []entry
+}
+
+// NewIndex returns an empty index using DefaultThreshold.
+func NewIndex() *Index {
+	return &Index{Threshold: DefaultThreshold}
+}
+
+// Add puts a question in the index.
+func (x *Index) Add(question models.Question) {
+	x.entries = append(x.entries, newEntry(question))
+}
+
+// Len returns how many questions have been added.
+func (x *Index) Len() int {
+	return len(x.entries)
+}
+
+// Find returns the first question in the index that the given one repeats, if any. An exact
+// repeat is preferred over a similar one.
+func (x *Index) Find(question models.Question) (Match, bool) {
+	candidate := newEntry(question)
+	best, found := Match{}, false
+	for position, existing := range x.entries {
+		match, repeats := compare(candidate, existing, x.Threshold)
+		if !repeats {
+			continue
+		}
+		if match.Exact {
+			match.Position = position
+			return match, true
+		}
+		if !found {
+			best, found = match, true
+			best.Position = position
+		}
+	}
+	return best, found
+}
+
+// Unique returns the questions that do not repeat an earlier one, in order.
+func Unique(questions []models.Question) []models.Question {
+	index := NewIndex()
+	unique := make([]models.Question, 0, len(questions))
+	for _, question := range questions {
+		if _, repeats := index.Find(question); repeats {
+			continue
+		}
+		index.Add(question)
+		unique = append(unique, question)
+	}
+	return unique
+}
+
+// Pair is two questions suspected to be the same.
+type Pair struct {
+	First  models.Question `json:"first"`
+	Second models.Question `json:"second"`
+	Match
+}
+
+// Pairs lists every pair of questions suspected to be the same, most alike first.
+func Pairs(questions []models.Question, threshold int) []Pair {
+	entries := make([]entry, len(questions))
+	for i, question := range questions {
+		entries[i] = newEntry(question)
+	}
+
+	var pairs []Pair
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			if match, repeats := compare(entries[j], entries[i], threshold); repeats {
+				pairs = append(pairs, Pair{First: questions[i], Second: questions[j], Match: match})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].Exact != pairs[j].Exact {
+			return pairs[i].Exact
+		}
+		return pairs[i].Distance < pairs[j].Distance
+	})
+	return pairs
+}
+
+// newEntry reduces a question to what is compared.
+func newEntry(question models.Question) entry {
+	return entry{
+		text:   grading.Normalize(question.QuestionText),
+		answer: AnswerKey(question),
+		hash:   Simhash(question.QuestionText),
+	}
+}
+
+// compare reports whether one question repeats another.
+func compare(candidate, existing entry, threshold int) (Match, bool) {
+	distance := Distance(candidate.hash, existing.hash)
+	if candidate.text == existing.text && candidate.answer == existing.answer {
+		return Match{Exact: true, Distance: distance}, true
+	}
+	if candidate.answer == existing.answer && distance <= threshold {
+		return Match{Distance: distance}, true
+	}
+	return Match{}, false
+}
@@ -0,0 +1,45 @@
+package dedupe
+
+import (
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func choice(text, answer string, options ...string) models.Question {
+	return models.Question{QuestionText: text, Options: append(options, answer), CorrectIndex: len(options)}
+}
+
+func TestRewordedQuestionsWithTheSameAnswerAreDuplicates(t *testing.T) {
+	questions := []models.Question{
+		choice("What is the capital of France?", "Paris", "Lyon", "Nice"),
+		choice("What is the capital city of France?", "Paris", "Marseille"),
+		choice("What is the capital of Spain?", "Madrid", "Seville"),
+		choice("Which planet is known as the Red Planet?", "Mars", "Venus"),
+		choice("Which planet is known as the \"Red Planet\"?", "Mars", "Jupiter"),
+		choice("Which planet is the largest in our solar system?", "Jupiter", "Saturn"),
+		choice("Which planet has the Great Red Spot?", "Jupiter", "Neptune"),
+	}
+
+	unique := Unique(questions)
+	if len(unique) != 5 {
+		t.Fatalf("Expected 5 unique questions; got %d: %+v", len(unique), unique)
+	}
+	for i, want := range []int{0, 2, 3, 5, 6} {
+		if unique[i].QuestionText != questions[want].QuestionText {
+			t.Errorf("Expected question %d to be %q; got %q", i, questions[want].QuestionText, unique[i].QuestionText)
+		}
+	}
+
+	if sameText := Unique([]models.Question{
+		choice("Which of these is a fruit?", "Apple", "Carrot"),
+		choice("Which of these is a fruit?", "Banana", "Potato"),
+	}); len(sameText) != 2 {
+		t.Errorf("Questions worded the same with different answers should both be kept; got %+v", sameText)
+	}
+
+	pairs := Pairs(questions, DefaultThreshold)
+	if len(pairs) != 2 || !pairs[0].Exact || pairs[0].First.QuestionText != questions[3].QuestionText || pairs[1].Exact {
+		t.Errorf("Expected the exact Red Planet pair, then the reworded France pair; got %+v", pairs)
+	}
+}
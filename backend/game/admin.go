@@ -0,0 +1,202 @@
+package game
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gclluch/TriviaApp-ReactGo/bank"
+	"github.com/gclluch/TriviaApp-ReactGo/dedupe"
+	"github.com/gclluch/TriviaApp-ReactGo/importer"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize caps the size of an uploaded question file.
+const maxImportSize = 10 << 20
+
+// RequireAdmin lets a request through only if it carries the admin token as a bearer token.
+func (gs *GameServer) RequireAdmin(c *gin.Context) {
+	if gs.AdminToken == "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin API is disabled"})
+		return
+	}
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || subtle.ConstantTimeCompare([]byte(token), []byte(gs.AdminToken)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Admin token required"})
+		return
+	}
+	c.Next()
+}
+
+// ImportQuestionsHandler adds the questions in an uploaded file to the local bank. The file is
+// sent as the multipart field "file"; its format is the "format" field, or else guessed from
+// its name. With "dryRun" set, the file is only checked. A file with any problem imports
+// nothing, and every problem is listed with its line. Questions repeating one already in the
+// bank, or earlier in the file, are listed as duplicates; exact repeats are skipped.
+func (gs *GameServer) ImportQuestionsHandler(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	upload, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload the questions as the multipart field \"file\""})
+		return
+	}
+
+	format := importer.Format(c.PostForm("format"))
+	if format == "" {
+		var known bool
+		if format, known = importer.FormatOf(upload.Filename); !known {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Give the file's format; it cannot be told from its name"})
+			return
+		}
+	}
+
+	file, err := upload.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read upload"})
+		return
+	}
+	defer file.Close()
+
+	questions, duplicates, err := importer.Import(file, format, gs.Bank.Questions())
+	var problems importer.Errors
+	switch {
+	case errors.As(err, &problems):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The file has problems; nothing was imported", "problems": problems})
+		return
+	case errors.Is(err, importer.ErrUnknownFormat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.PostForm("dryRun") == "true" {
+		c.JSON(http.StatusOK, gin.H{"dryRun": true, "valid": len(questions), "duplicates": duplicates})
+		return
+	}
+	added, err := gs.Bank.Add(questions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save questions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"imported": len(added), "questions": added, "duplicates": duplicates})
+}
+
+// SearchQuestionsHandler lists the bank questions matching the query parameters q (words in
+// the question or its answers), category, difficulty, type, source and tag.
+func (gs *GameServer) SearchQuestionsHandler(c *gin.Context) {
+	questions := gs.Bank.Search(bank.Filter{
+		Text:       c.Query("q"),
+		Category:   c.Query("category"),
+		Difficulty: c.Query("difficulty"),
+		Type:       c.Query("type"),
+		Source:     c.Query("source"),
+		Tag:        c.Query("tag"),
+	})
+	c.JSON(http.StatusOK, gin.H{"questions": questions, "total": len(questions)})
+}
+
+// GetQuestionHandler returns one bank question.
+func (gs *GameServer) GetQuestionHandler(c *gin.Context) {
+	question, err := gs.Bank.Get(c.Param("questionId"))
+	writeBankResult(c, http.StatusOK, gin.H{"question": question}, err)
+}
+
+// CreateQuestionHandler adds a question to the bank.
+func (gs *GameServer) CreateQuestionHandler(c *gin.Context) {
+	var question models.Question
+	if err := c.ShouldBindJSON(&question); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	created, err := gs.Bank.Create(question)
+	writeBankResult(c, http.StatusCreated, gin.H{"question": created}, err)
+}
+
+// UpdateQuestionHandler replaces a bank question.
+func (gs *GameServer) UpdateQuestionHandler(c *gin.Context) {
+	var question models.Question
+	if err := c.ShouldBindJSON(&question); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	updated, err := gs.Bank.Update(c.Param("questionId"), question)
+	writeBankResult(c, http.StatusOK, gin.H{"question": updated}, err)
+}
+
+// TagQuestionHandler adds tags to and removes tags from a bank question.
+func (gs *GameServer) TagQuestionHandler(c *gin.Context) {
+	var requestBody struct {
+		Add    []string `json:"add"`
+		Remove []string `json:"remove"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	tagged, err := gs.Bank.Tag(c.Param("questionId"), requestBody.Add, requestBody.Remove)
+	writeBankResult(c, http.StatusOK, gin.H{"question": tagged}, err)
+}
+
+// DeleteQuestionHandler retires a bank question. Its history is kept.
+func (gs *GameServer) DeleteQuestionHandler(c *gin.Context) {
+	err := gs.Bank.Delete(c.Param("questionId"))
+	writeBankResult(c, http.StatusOK, gin.H{"deleted": c.Param("questionId")}, err)
+}
+
+// QuestionHistoryHandler lists every revision of a bank question, oldest first.
+func (gs *GameServer) QuestionHistoryHandler(c *gin.Context) {
+	revisions, err := gs.Bank.History(c.Param("questionId"))
+	writeBankResult(c, http.StatusOK, gin.H{"revisions": revisions}, err)
+}
+
+// DuplicatesHandler lists the pairs of bank questions suspected to be the same, word for word
+// or reworded with the same answer, most alike first. The query parameter threshold sets how
+// many simhash bits apart reworded questions may be.
+func (gs *GameServer) DuplicatesHandler(c *gin.Context) {
+	threshold := dedupe.DefaultThreshold
+	if value := c.Query("threshold"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > 64 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "threshold must be a number of bits from 0 to 64"})
+			return
+		}
+		threshold = parsed
+	}
+	pairs := dedupe.Pairs(gs.Bank.Questions(), threshold)
+	c.JSON(http.StatusOK, gin.H{"duplicates": pairs, "total": len(pairs)})
+}
+
+// MergeDuplicatesHandler resolves a pair of duplicates by deleting the question "remove" and
+// keeping "keep", which gains its tags.
+func (gs *GameServer) MergeDuplicatesHandler(c *gin.Context) {
+	var requestBody struct {
+		Keep   string `json:"keep" binding:"required"`
+		Remove string `json:"remove" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	kept, err := gs.Bank.Merge(requestBody.Keep, requestBody.Remove)
+	writeBankResult(c, http.StatusOK, gin.H{"question": kept, "deleted": requestBody.Remove}, err)
+}
+
+// writeBankResult writes the response to a bank change: the given body on success, or the
+// error mapped to its status.
+func writeBankResult(c *gin.Context, status int, body gin.H, err error) {
+	switch {
+	case errors.Is(err, bank.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, bank.ErrInvalidQuestion):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(status, body)
+	}
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gclluch/TriviaApp-ReactGo/origin"
+	"github.com/gclluch/TriviaApp-ReactGo/session"
+	"github.com/gclluch/TriviaApp-ReactGo/store"
+	"github.com/gin-gonic/gin"
+)
+
+// newTestGame creates a game server with one session asking the given questions, and a
+// router serving the game's endpoints.
+func newTestGame(t *testing.T, options models.GameOptions, questions []models.Question) (*GameServer, *gin.Engine, *session.PlayerSession) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	sessionStore := store.NewSessionStore()
+	t.Cleanup(sessionStore.Close)
+	gameServer := NewGameServer(sessionStore, origin.NewPolicy(nil))
+
+	sessionID, err := sessionStore.CreateSessionWithQuestions(options, questions)
+	if err != nil {
+		t.Fatalf("CreateSessionWithQuestions failed: %v", err)
+	}
+	playerSession, _ := sessionStore.GetSession(sessionID)
+
+	router := gin.New()
+	router.POST("/answer", gameServer.AnswerHandler)
+	router.GET("/ws", gameServer.WebSocketEndpoint)
+	return gameServer, router, playerSession
+}
+
+// postAnswer submits an answer and decodes the response.
+func postAnswer(router *gin.Engine, submission models.AnswerSubmission) (int, map[string]interface{}) {
+	body, _ := json.Marshal(submission)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/answer", bytes.NewReader(body)))
+	var response map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder.Code, response
+}
+
+func TestOnlyTheFirstCorrectAnswerScores(t *testing.T) {
+	questions := []models.Question{{ID: "1", QuestionText: "Q", Options: []string{"A", "B"}, CorrectIndex: 0}}
+	_, router, playerSession := newTestGame(t, models.GameOptions{}, questions)
+	players := make([]*models.Player, 8)
+	for i := range players {
+		players[i] = playerSession.AddPlayer()
+	}
+
+	var wg sync.WaitGroup
+	responses := make([]map[string]interface{}, len(players))
+	for i, player := range players {
+		wg.Add(1)
+		go func(i int, player *models.Player) {
+			defer wg.Done()
+			code, response := postAnswer(router, models.AnswerSubmission{
+				SessionID: playerSession.ID, ResumeToken: player.ResumeToken, QuestionID: "1", Answer: 0,
+			})
+			if code != http.StatusOK {
+				t.Errorf("Expected 200; got %d %v", code, response)
+			}
+			responses[i] = response
+		}(i, player)
+	}
+	wg.Wait()
+
+	scored, total := 0, 0
+	for i, response := range responses {
+		if response["correct"] == true {
+			scored++
+		}
+		total += players[i].Score
+	}
+	if scored != 1 || total != session.BasePoints {
+		t.Errorf("Expected exactly one player to score %d; got %d scoring %d in all", session.BasePoints, scored, total)
+	}
+}
+
+func TestPartialCreditDoesNotCountAsCorrect(t *testing.T) {
+	questions := []models.Question{{
+		ID:             "1",
+		Type:           models.QuestionMultiSelect,
+		QuestionText:   "Which are primes?",
+		Options:        []string{"2", "3", "4", "6"},
+		CorrectIndexes: []int{0, 1},
+	}}
+	_, router, playerSession := newTestGame(t, models.GameOptions{}, questions)
+	partly, fully := playerSession.AddPlayer(), playerSession.AddPlayer()
+
+	code, response := postAnswer(router, models.AnswerSubmission{
+		SessionID: playerSession.ID, ResumeToken: partly.ResumeToken, QuestionID: "1", Selections: []int{0},
+	})
+	if code != http.StatusOK || response["awarded"] != true || response["correct"] != false {
+		t.Fatalf("Expected a partly right answer to earn credit without being correct; got %d %v", code, response)
+	}
+	postAnswer(router, models.AnswerSubmission{
+		SessionID: playerSession.ID, ResumeToken: fully.ResumeToken, QuestionID: "1", Selections: []int{0, 1},
+	})
+
+	if partly.Score <= 0 || partly.Correct != 0 {
+		t.Errorf("Expected partial credit to score without counting as correct; got score %d, correct %d", partly.Score, partly.Correct)
+	}
+	if fully.Score != session.BasePoints || fully.Correct != 1 {
+		t.Errorf("Expected the fully right answer to score and count; got score %d, correct %d", fully.Score, fully.Correct)
+	}
+}
+
+func TestAnswersNeedTheirPlayersResumeToken(t *testing.T) {
+	questions := []models.Question{{ID: "1", QuestionText: "Q", Options: []string{"A", "B"}, CorrectIndex: 0}}
+	_, router, playerSession := newTestGame(t, models.GameOptions{}, questions)
+	player := playerSession.AddPlayer()
+
+	for _, token := range []string{"forged", ""} {
+		code, response := postAnswer(router, models.AnswerSubmission{
+			SessionID: playerSession.ID, ResumeToken: token, QuestionID: "1", Answer: 0,
+		})
+		if code != http.StatusForbidden {
+			t.Errorf("Expected an answer with resume token %q to be refused; got %d %v", token, code, response)
+		}
+	}
+	if player.Score != 0 || player.Answered["1"] {
+		t.Errorf("A refused answer should not count for the player; got %+v", player)
+	}
+
+	if code, response := postAnswer(router, models.AnswerSubmission{
+		SessionID: playerSession.ID, ResumeToken: player.ResumeToken, QuestionID: "1", Answer: 0,
+	}); code != http.StatusOK || response["correct"] != true {
+		t.Errorf("Expected the player's own answer to score; got %d %v", code, response)
+	}
+}
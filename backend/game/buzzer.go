@@ -0,0 +1,116 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gclluch/TriviaApp-ReactGo/session"
+	"github.com/gin-gonic/gin"
+)
+
+// BuzzHandler records a buzz over HTTP, for clients following the game over Server-Sent Events.
+// The player buzzing is named by their resume token.
+func (gs *GameServer) BuzzHandler(c *gin.Context) {
+	var requestBody struct {
+		SessionID   string `json:"sessionId"`
+		ResumeToken string `json:"resumeToken"`
+		QuestionID  string `json:"questionId"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	playerSession, ok := gs.retrieveSession(c, requestBody.SessionID)
+	if !ok {
+		return
+	}
+	player, ok := gs.authenticatePlayer(c, playerSession, requestBody.ResumeToken)
+	if !ok {
+		return
+	}
+
+	buzz, err := gs.buzz(playerSession, player.ID, requestBody.QuestionID)
+	switch {
+	case errors.Is(err, session.ErrPlayerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+	case err != nil:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "buzzer": playerSession.BuzzerState(requestBody.QuestionID)})
+	default:
+		c.JSON(http.StatusOK, gin.H{"accepted": true, "buzzedAt": buzz.BuzzedAt, "lockedUntil": buzz.BuzzedAt.Add(playerSession.BuzzWindow)})
+	}
+}
+
+// handleBuzz processes a "buzz" action from a WebSocket connection bound to a player.
+func (gs *GameServer) handleBuzz(message map[string]interface{}, client *wsClient) {
+	if client.session == nil || client.playerID == "" {
+		gs.rejectAction(client, "buzz", "resume the session as a player first")
+		return
+	}
+
+	questionID, _ := message["questionId"].(string)
+	if _, err := gs.buzz(client.session, client.playerID, questionID); err != nil {
+		gs.rejectAction(client, "buzz", err.Error())
+	}
+}
+
+// buzz validates and records a buzz, announcing the new buzzer holder to everyone.
+func (gs *GameServer) buzz(playerSession *session.PlayerSession, playerID, questionID string) (session.Buzz, error) {
+	if playerSession.Mode != session.ModeBuzzer {
+		return session.Buzz{}, errors.New("session is not in buzzer mode")
+	}
+	if !playerSession.Reached(questionID) {
+		return session.Buzz{}, errors.New("question not found")
+	}
+	if playerSession.IsPaused() {
+		return session.Buzz{}, session.ErrPaused
+	}
+
+	buzz, err := playerSession.Buzz(playerID, questionID)
+	if err != nil {
+		return buzz, err
+	}
+	playerSession.BroadcastBuzz(questionID, buzz)
+	return buzz, nil
+}
+
+// answerBuzzer applies buzzer rules to a multiplayer answer: only the buzzer holder may
+// answer, a correct answer wins the question and a wrong one reopens it. Refused answers
+// are not recorded as answered.
+func (gs *GameServer) answerBuzzer(c *gin.Context, playerSession *session.PlayerSession, player *models.Player, submission models.AnswerSubmission, correct bool) {
+	if err := playerSession.ResolveBuzz(player.ID, submission.QuestionID, correct); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	gs.recordAnswer(playerSession.Questions, submission)
+	playerSession.MarkAnswered(player.ID, submission.QuestionID)
+
+	if correct {
+		playerSession.UpdatePlayerScore(player.ID, submission.QuestionID, playerSession.PointsFor(submission.QuestionID))
+		playerSession.Broadcast(map[string]interface{}{
+			"type":       "buzzWon",
+			"questionId": submission.QuestionID,
+			"playerId":   player.ID,
+			"playerName": player.Name,
+		})
+		playerSession.BroadcastHighScore()
+		if playerSession.TeamMode {
+			playerSession.BroadcastTeamScores()
+		}
+	} else {
+		playerSession.BroadcastBuzzReopened(submission.QuestionID, "wrongAnswer")
+	}
+
+	c.JSON(http.StatusOK, gin.H{"correct": correct, "currentScore": player.Score})
+}
+
+// findQuestion looks up a question by its ID.
+func findQuestion(questions []models.Question, questionID string) (models.Question, bool) {
+	for _, question := range questions {
+		if question.ID == questionID {
+			return question, true
+		}
+	}
+	return models.Question{}, false
+}
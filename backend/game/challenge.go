@@ -0,0 +1,116 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/challenge"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateChallengeHandler turns a finished single-player game into a challenge another player
+// can replay later. The response carries the challenge ID for the link. Only the user who
+// played the game, named by their user token, may send it.
+func (gs *GameServer) CreateChallengeHandler(c *gin.Context) {
+	var requestBody struct {
+		UserToken  string `json:"userToken"`
+		PlayerName string `json:"playerName"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	playerSession, ok := gs.retrieveSession(c, c.Param("sessionId"))
+	if !ok {
+		return
+	}
+	if !playerSession.Solo() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only single-player classic games can be sent as challenges"})
+		return
+	}
+	if !playerSession.SoloFinished() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Finish the game before sending a challenge"})
+		return
+	}
+	userID, ok := gs.verifyUser(c, requestBody.UserToken)
+	if !ok {
+		return
+	}
+	playerSession.Lock()
+	player := playerSession.SoloUserID
+	playerSession.Unlock()
+	if userID != player {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the player of this game may send it as a challenge"})
+		return
+	}
+
+	created := gs.Challenges.Create(
+		challenge.Result{UserID: userID, PlayerName: requestBody.PlayerName, Score: playerSession.Score, Finished: true, FinishedAt: time.Now()},
+		playerSession.PlayedQuestions(),
+		playerSession.Options,
+	)
+	c.JSON(http.StatusOK, gin.H{"challengeId": created.ID, "expiresAt": created.ExpiresAt})
+}
+
+// GetChallengeHandler reports a challenge's status, including the comparison once both players have finished.
+func (gs *GameServer) GetChallengeHandler(c *gin.Context) {
+	found, err := gs.Challenges.Get(c.Param("challengeId"))
+	if err != nil {
+		gs.challengeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"challenge": found, "numQuestions": len(found.Questions)})
+}
+
+// AcceptChallengeHandler starts the recipient's game: a single-player session with the
+// challenger's questions, option order and game options, such as rounds, scoring and time
+// limit. A challenge can be accepted once.
+func (gs *GameServer) AcceptChallengeHandler(c *gin.Context) {
+	var requestBody struct {
+		PlayerName string `json:"playerName"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	challengeID := c.Param("challengeId")
+	accepted, err := gs.Challenges.Accept(challengeID, requestBody.PlayerName)
+	if err != nil {
+		gs.challengeError(c, err)
+		return
+	}
+
+	// The challenger's history has no bearing on the recipient's game.
+	options := accepted.Options
+	options.UserIDs = nil
+	sessionID, err := gs.Store.CreateSessionWithQuestions(options, accepted.Questions)
+	if err != nil {
+		gs.Challenges.Cancel(challengeID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start challenge"})
+		return
+	}
+	gs.Challenges.Attach(challengeID, sessionID)
+	replay, _ := gs.Store.GetSession(sessionID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessionId":    sessionID,
+		"numQuestions": len(accepted.Questions),
+		"timeLimit":    int(replay.TimeLimit / time.Second),
+		"challenger":   accepted.Challenger,
+	})
+}
+
+// challengeError maps a challenge lookup error to its HTTP status.
+func (gs *GameServer) challengeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, challenge.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, challenge.ErrExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	}
+}
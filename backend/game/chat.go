@@ -0,0 +1,129 @@
+package game
+
+import (
+	"log"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// boundPlayer returns the player a connection is bound to, telling the client why an
+// action was refused when it is not bound to one. Connections are bound only by resuming
+// with the player's resume token, so a player cannot act as another by naming them.
+func (gs *GameServer) boundPlayer(action string, client *wsClient) (*models.Player, bool) {
+	if client.session == nil || client.playerID == "" {
+		gs.rejectAction(client, action, "resume the session as a player first")
+		return nil, false
+	}
+
+	client.session.Lock()
+	player, exists := client.session.Players[client.playerID]
+	bound := client.session.Subscribers[client.subscriber] == client.playerID
+	client.session.Unlock()
+	if !bound {
+		gs.rejectAction(client, action, "resume the session as a player first")
+		return nil, false
+	}
+	if !exists {
+		gs.rejectAction(client, action, "player not found")
+		return nil, false
+	}
+	return player, true
+}
+
+// hostPlayer returns the bound player if they host the session.
+func (gs *GameServer) hostPlayer(action string, client *wsClient) (*models.Player, bool) {
+	player, ok := gs.boundPlayer(action, client)
+	if !ok {
+		return nil, false
+	}
+
+	client.session.Lock()
+	isHost := client.session.HostID == player.ID
+	client.session.Unlock()
+	if !isHost {
+		gs.rejectAction(client, action, "only the host can do that")
+		return nil, false
+	}
+	return player, true
+}
+
+// rejectAction tells a single client that its action was refused.
+func (gs *GameServer) rejectAction(client *wsClient, action, reason string) {
+	message := map[string]interface{}{"type": "actionRejected", "action": action, "reason": reason}
+	if client.session != nil {
+		client.session.SendTo(client.subscriber, message)
+		return
+	}
+	log.Printf("Rejected %s: %s", action, reason)
+}
+
+// handleChat processes a "chat" action, broadcasting the moderated message to the session.
+func (gs *GameServer) handleChat(message map[string]interface{}, client *wsClient) {
+	player, ok := gs.boundPlayer("chat", client)
+	if !ok {
+		return
+	}
+
+	text, _ := message["text"].(string)
+	posted, err := client.session.Chat.Post(player.ID, player.Name, text)
+	if err != nil {
+		gs.rejectAction(client, "chat", err.Error())
+		return
+	}
+
+	client.session.Broadcast(map[string]interface{}{"type": "chat", "message": posted})
+}
+
+// handleReaction processes a "react" action, broadcasting a quick emoji reaction.
+func (gs *GameServer) handleReaction(message map[string]interface{}, client *wsClient) {
+	player, ok := gs.boundPlayer("react", client)
+	if !ok {
+		return
+	}
+
+	reaction, _ := message["reaction"].(string)
+	if err := client.session.Chat.React(player.ID, reaction); err != nil {
+		gs.rejectAction(client, "react", err.Error())
+		return
+	}
+
+	client.session.Broadcast(map[string]interface{}{
+		"type":       "reaction",
+		"playerId":   player.ID,
+		"playerName": player.Name,
+		"reaction":   reaction,
+	})
+}
+
+// handleMuteChat processes a host's "muteChat" action for a single player.
+func (gs *GameServer) handleMuteChat(message map[string]interface{}, client *wsClient) {
+	if _, ok := gs.hostPlayer("muteChat", client); !ok {
+		return
+	}
+
+	playerID, _ := message["playerId"].(string)
+	muted, ok := message["muted"].(bool)
+	if playerID == "" || !ok {
+		gs.rejectAction(client, "muteChat", "'playerId' and 'muted' are required")
+		return
+	}
+
+	client.session.Chat.SetMuted(playerID, muted)
+	client.session.Broadcast(map[string]interface{}{"type": "playerMuted", "playerId": playerID, "muted": muted})
+}
+
+// handleSetChatEnabled processes a host's "setChatEnabled" action for the whole session.
+func (gs *GameServer) handleSetChatEnabled(message map[string]interface{}, client *wsClient) {
+	if _, ok := gs.hostPlayer("setChatEnabled", client); !ok {
+		return
+	}
+
+	enabled, ok := message["enabled"].(bool)
+	if !ok {
+		gs.rejectAction(client, "setChatEnabled", "'enabled' is required")
+		return
+	}
+
+	client.session.Chat.SetEnabled(enabled)
+	client.session.Broadcast(map[string]interface{}{"type": "chatStatus", "enabled": enabled})
+}
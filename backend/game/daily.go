@@ -0,0 +1,75 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/daily"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gin-gonic/gin"
+)
+
+// DailyStartHandler starts a player's one attempt at today's challenge. The attempt is a
+// single-player session playing the day's shared question set. Players are known by the
+// user ID their user token proves, so a new attempt needs a new identity from the server.
+func (gs *GameServer) DailyStartHandler(c *gin.Context) {
+	var requestBody struct {
+		UserToken  string `json:"userToken"`
+		PlayerName string `json:"playerName"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if requestBody.UserToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": daily.ErrMissingPlayer.Error()})
+		return
+	}
+	userID, ok := gs.verifyUser(c, requestBody.UserToken)
+	if !ok {
+		return
+	}
+
+	date := daily.Today()
+	questions, err := gs.Daily.Questions(date)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	attempt, err := gs.Daily.Start(date, userID, requestBody.PlayerName)
+	switch {
+	case errors.Is(err, daily.ErrMissingPlayer):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, daily.ErrAlreadyAttempted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "date": date})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	sessionID, err := gs.Store.CreateSessionWithQuestions(models.GameOptions{}, questions)
+	if err != nil {
+		gs.Daily.Cancel(date, attempt)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start daily challenge"})
+		return
+	}
+	gs.Daily.Attach(attempt, sessionID)
+	gs.setSoloUser(sessionID, userID)
+
+	c.JSON(http.StatusOK, gin.H{"sessionId": sessionID, "date": date, "numQuestions": len(questions)})
+}
+
+// DailyLeaderboardHandler ranks the attempts at a day's challenge, today's unless a date is given.
+func (gs *GameServer) DailyLeaderboardHandler(c *gin.Context) {
+	date := c.DefaultQuery("date", daily.Today())
+	if _, err := time.Parse(daily.DateFormat, date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be formatted as YYYY-MM-DD"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"date": date, "leaderboard": gs.Daily.Leaderboard(date)})
+}
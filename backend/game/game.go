@@ -2,55 +2,90 @@ package game
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"sort"
 	"sync"
+	"time"
 
+	"github.com/gclluch/TriviaApp-ReactGo/bank"
+	"github.com/gclluch/TriviaApp-ReactGo/challenge"
+	"github.com/gclluch/TriviaApp-ReactGo/daily"
+	"github.com/gclluch/TriviaApp-ReactGo/grading"
+	"github.com/gclluch/TriviaApp-ReactGo/identity"
 	"github.com/gclluch/TriviaApp-ReactGo/models"
-	"github.com/gclluch/TriviaApp-ReactGo/services"
+	"github.com/gclluch/TriviaApp-ReactGo/origin"
 	"github.com/gclluch/TriviaApp-ReactGo/session"
 	"github.com/gclluch/TriviaApp-ReactGo/store"
+	"github.com/gclluch/TriviaApp-ReactGo/tournament"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
 
 // GameServer struct encapsulates dependencies for the game logic.
 type GameServer struct {
-	Store       *store.SessionStore
-	Upgrader    websocket.Upgrader
-	Leaderboard []models.LeaderboardEntry
-	mutex       sync.Mutex
+	Store          *store.SessionStore
+	Upgrader       websocket.Upgrader
+	Leaderboard    []models.LeaderboardEntry
+	ReconnectGrace time.Duration         // How long a dropped player may take to resume before leaving.
+	Daily          *daily.Challenges     // Daily challenge attempts and leaderboards.
+	Challenges     *challenge.Challenges // Asynchronous head-to-head challenges.
+	Tournaments    *tournament.Manager   // Tournaments whose matches are played in this server's sessions.
+	Bank           *bank.Bank            // Local question bank that admins import into.
+	AdminToken     string                // Bearer token for the admin API; empty disables it.
+	Identity       *identity.Issuer      // Issues and checks players' user tokens.
+	mutex          sync.Mutex
 }
 
-// NewGameServer initializes a new GameServer instance.
-func NewGameServer(store *store.SessionStore) *GameServer {
+// NewGameServer initializes a new GameServer instance. WebSocket upgrades are only
+// accepted from origins permitted by the given policy.
+func NewGameServer(store *store.SessionStore, origins *origin.Policy) *GameServer {
 	return &GameServer{
-		Store: store,
+		Store:          store,
+		ReconnectGrace: session.DefaultReconnectGrace,
+		Daily:          daily.NewChallenges(daily.Config{}),
+		Challenges:     challenge.NewChallenges(challenge.DefaultTTL),
+		Tournaments:    tournament.NewManager(store),
 		Upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool {
-				return true // Allow all origins for demo purposes; adjust as necessary.
-			},
+			CheckOrigin: origins.CheckRequest,
 		},
 	}
 }
 
-// StartGameHandler initiates a new game session.
+// StartGameHandler initiates a new game session. A returning player's client sends their user
+// token, so a single-player game avoids the questions they have answered and remembers the
+// ones they answer now.
 func (gs *GameServer) StartGameHandler(c *gin.Context) {
 	var requestBody struct {
-		NumQuestions int `json:"numQuestions"`
+		models.GameOptions
+		UserToken string `json:"userToken"`
 	}
 
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		requestBody.NumQuestions = 10
 	}
+	userID := ""
+	if requestBody.UserToken != "" {
+		var ok bool
+		if userID, ok = gs.verifyUser(c, requestBody.UserToken); !ok {
+			return
+		}
+		requestBody.UserIDs = append(requestBody.UserIDs, userID)
+	}
 
-	sessionID, err := gs.Store.CreateSession(requestBody.NumQuestions)
+	sessionID, err := gs.Store.CreateSession(requestBody.GameOptions)
+	if errors.Is(err, store.ErrInvalidOptions) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
 		return
 	}
+	gs.setSoloUser(sessionID, userID)
 
 	// Generate a shareable link for the session.
 	shareableLink := fmt.Sprintf("%s/join/%s", c.Request.Host, sessionID)
@@ -62,30 +97,147 @@ func (gs *GameServer) StartGameHandler(c *gin.Context) {
 }
 
 // JoinGameHandler adds a player to an existing game session.
+// In team mode the player may pick a team; otherwise they are auto-balanced onto one.
+// Tournament match sessions only admit the match's entrants, who join under their entrant ID
+// with the entrant token they were issued at registration.
+// A returning player's client sends their user token, so the questions they answer are
+// remembered and not asked of them again.
 func (gs *GameServer) JoinGameHandler(c *gin.Context) {
+	var requestBody struct {
+		Team         string `json:"team"`
+		EntrantID    string `json:"entrantId"`
+		EntrantToken string `json:"entrantToken"`
+		UserToken    string `json:"userToken"`
+	}
+	_ = c.ShouldBindJSON(&requestBody) // The body is optional.
+	userID := ""
+	if requestBody.UserToken != "" {
+		var ok bool
+		if userID, ok = gs.verifyUser(c, requestBody.UserToken); !ok {
+			return
+		}
+	}
+
 	sessionID := c.Param("sessionId")
 	session, ok := gs.retrieveSession(c, sessionID)
 	if !ok {
 		return
 	}
+	if session.TeamMode && requestBody.Team != "" && !session.HasTeam(requestBody.Team) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown team"})
+		return
+	}
+	entrantName, isMatch, ok := gs.seatEntrant(c, sessionID, requestBody.EntrantID, requestBody.EntrantToken)
+	if !ok {
+		return
+	}
+
 	player := session.AddPlayer()
+	session.Lock()
+	player.UserID = userID
+	session.Unlock()
+	if isMatch {
+		session.Lock()
+		player.Name = entrantName
+		session.Unlock()
+		gs.Tournaments.Bind(sessionID, requestBody.EntrantID, player.ID)
+	}
+	if session.TeamMode {
+		if err := session.AssignTeam(player.ID, requestBody.Team); err != nil {
+			log.Printf("Failed to assign player %s to a team: %v", player.ID, err)
+		}
+		session.BroadcastTeamScores()
+	}
 
 	// Broadcast the updated player count to all clients in the session
 	session.BroadcastPlayerCount()
 
 	// Start the countdown when the first player joins
 	if len(session.Players) == 1 {
-		go session.StartCountdown(5)
+		go gs.runSession(session)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Player joined successfully.",
+		"playerId":    player.ID,
+		"playerName":  player.Name,
+		"resumeToken": player.ResumeToken,
+		"isHost":      session.HostID == player.ID,
+		"team":        player.Team,
+	})
+}
+
+// SwitchTeamHandler moves a player to another team before the game starts. The player is
+// named by their resume token, so only they can move themselves.
+func (gs *GameServer) SwitchTeamHandler(c *gin.Context) {
+	var requestBody struct {
+		ResumeToken string `json:"resumeToken"`
+		Team        string `json:"team"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	playerSession, ok := gs.retrieveSession(c, c.Param("sessionId"))
+	if !ok {
+		return
+	}
+	if !playerSession.TeamMode {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Session is not in team mode"})
+		return
+	}
+	player, ok := gs.authenticatePlayer(c, playerSession, requestBody.ResumeToken)
+	if !ok {
+		return
 	}
 
+	switch err := playerSession.AssignTeam(player.ID, requestBody.Team); {
+	case errors.Is(err, session.ErrPlayerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		playerSession.BroadcastTeamScores()
+		c.JSON(http.StatusOK, gin.H{"message": "Team updated.", "team": requestBody.Team})
+	}
+}
+
+// ResumeGameHandler restores a player's identity from the resume token issued at join. The
+// player still has to resume over a WebSocket or event stream within the reconnect grace
+// window, or they leave as if they had disconnected.
+func (gs *GameServer) ResumeGameHandler(c *gin.Context) {
+	var requestBody struct {
+		ResumeToken string `json:"resumeToken"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil || requestBody.ResumeToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	session, ok := gs.retrieveSession(c, c.Param("sessionId"))
+	if !ok {
+		return
+	}
+
+	player, ok := session.ResumePlayer(requestBody.ResumeToken)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+	session.ExpectConnection(player.ID)
+
 	c.JSON(http.StatusOK, gin.H{
-		"message":    "Player joined successfully.",
-		"playerId":   player.ID,
-		"playerName": player.Name,
+		"message":     "Player resumed successfully.",
+		"playerId":    player.ID,
+		"playerName":  player.Name,
+		"resumeToken": player.ResumeToken,
+		"snapshot":    session.Snapshot(player.ID),
 	})
 }
 
-// QuestionsHandler returns a set of questions for the game.
+// QuestionsHandler returns a set of questions for the game, without their answers or media.
+// In buzzer and elimination games only the questions reached so far are returned.
 func (gs *GameServer) QuestionsHandler(c *gin.Context) {
 	sessionID := c.Param("sessionId")
 	session, ok := gs.retrieveSession(c, sessionID)
@@ -93,10 +245,29 @@ func (gs *GameServer) QuestionsHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"questions": session.Questions})
+	c.JSON(http.StatusOK, gin.H{"questions": session.PublicQuestions()})
+}
+
+// QuestionHandler returns one question of the game as it is shown, with URLs for its media.
+// Clients fetch each question this way when they come to it, so media URLs expire a while
+// after the question is shown rather than after the game starts.
+func (gs *GameServer) QuestionHandler(c *gin.Context) {
+	session, ok := gs.retrieveSession(c, c.Param("sessionId"))
+	if !ok {
+		return
+	}
+
+	question, ok := session.ReachedQuestion(c.Param("questionId"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"question": question})
 }
 
 // AnswerHandler handles answer submissions and updates the player's score.
+// In a multiplayer game the player is named by their resume token, since player
+// IDs are broadcast to everyone in the session.
 func (gs *GameServer) AnswerHandler(c *gin.Context) {
 	var submission models.AnswerSubmission
 	if err := c.ShouldBindJSON(&submission); err != nil {
@@ -110,47 +281,137 @@ func (gs *GameServer) AnswerHandler(c *gin.Context) {
 	}
 
 	// Validate the answer and update the score
-	correct, questionExists := services.CheckAnswer(session.Questions, submission.QuestionID, submission.Answer)
+	question, questionExists := findQuestion(session.Questions, submission.QuestionID)
 	if !questionExists {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
 		return
 	}
+	grade, err := grading.Check(question, submission, session.AnswerTolerance)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	correct := grade.Verdict == grading.Correct
+
+	if session.IsPaused() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Game is paused"})
+		return
+	}
 
 	// Single Player logic
-	if submission.PlayerID == "" {
-		if correct {
-			session.UpdateScore(10)
+	if submission.ResumeToken == "" {
+		// Without a resume token only the single player may answer, never a spectator of a multiplayer game.
+		if !session.Solo() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Join the session as a player to answer"})
+			return
+		}
+		// A lone guess at a numeric question has nothing to be ranked against, so it scores by relative error.
+		if err := session.SubmitSoloAnswer(submission.QuestionID, grade.Credit); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
 		}
-		c.JSON(http.StatusOK, gin.H{"correct": correct, "currentScore": session.Score})
+		gs.recordAnswer(session.Questions, submission)
+		gs.Daily.RecordScore(session.ID, session.Score)
+		gs.Challenges.RecordScore(session.ID, session.Score, session.SoloFinished())
+		response := gin.H{"correct": correct, "currentScore": session.Score}
+		if grade.Verdict == grading.Ranked {
+			response["credit"] = grade.Credit
+			response["value"] = question.NumericValue()
+		}
+		if grading.PartialCredit(question) {
+			response["credit"] = grade.Credit
+		}
+		c.JSON(http.StatusOK, response)
+		session.AnnounceFinishedRounds()
 		return
 	}
 
 	// Multiplayer logic
-	player, ok := session.Players[submission.PlayerID]
+	player, ok := gs.authenticatePlayer(c, session, submission.ResumeToken)
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
 		return
 	}
 
-	// If question answered correctly for the first time, update the player's score.
-	addScore := correct && !session.AnsweredQuestions[submission.QuestionID]
-	if addScore {
-		session.UpdatePlayerScore(
-			submission.PlayerID,
-			submission.QuestionID,
-			10,
-		)
-		session.BroadcastHighScore()
+	defer session.AnnounceFinishedRounds()
+
+	// Other modes check an answer is allowed before recording it.
+	if gs.answerForMode(c, session, player, submission, correct) {
+		return
+	}
+
+	firstAnswer := session.MarkAnswered(player.ID, submission.QuestionID)
+	if firstAnswer {
+		gs.recordAnswer(session.Questions, submission)
+	}
+
+	// Partly-right answers earn every player their own share, for their first answer only.
+	if grading.PartialCredit(question) {
+		points := 0
+		if firstAnswer {
+			points = int(math.Round(float64(session.PointsFor(question.ID)) * grade.Credit))
+		}
+		awarded := points > 0
+		if awarded {
+			gs.awardPoints(session, player, question.ID, points, grade.Verdict == grading.Correct)
+		}
+		c.JSON(http.StatusOK, gin.H{"correct": correct, "credit": grade.Credit, "awarded": awarded, "currentScore": player.Score})
+		return
+	}
+
+	// Guesses at a numeric question are scored together once everyone has guessed.
+	if grade.Verdict == grading.Ranked {
+		if err := session.SubmitGuess(player.ID, question.ID, *submission.Number); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"correct": false, "pending": true, "currentScore": player.Score})
+		session.RevealSettledGuesses()
+		return
+	}
+
+	// A borderline typed answer waits for the host's decision instead of being marked wrong.
+	if grade.Verdict == grading.Borderline && session.HostReviews() {
+		review := session.QueueReview(player.ID, question.ID, submission.Text, question.Answers)
+		c.JSON(http.StatusOK, gin.H{"correct": false, "pending": true, "reviewId": review.ID, "currentScore": player.Score})
+		return
 	}
 
+	addScore := correct && gs.scoreAnswer(session, player, submission.QuestionID)
 	c.JSON(http.StatusOK, gin.H{"correct": addScore, "currentScore": player.Score})
 }
 
+// scoreAnswer awards the points for a correct answer in classic play: the first correct
+// answer to a question scores. In team mode every member may score, and the team rule
+// combines their points. It reports whether points were awarded.
+func (gs *GameServer) scoreAnswer(playerSession *session.PlayerSession, player *models.Player, questionID string) bool {
+	if !playerSession.ScoreFirstCorrect(player.ID, questionID, playerSession.PointsFor(questionID)) {
+		return false
+	}
+	gs.announceScores(playerSession)
+	return true
+}
+
+// awardPoints adds points to a player's score and announces the new standings. Only fully
+// correct answers count towards the player's correct answers.
+func (gs *GameServer) awardPoints(playerSession *session.PlayerSession, player *models.Player, questionID string, points int, correct bool) {
+	playerSession.AddCredit(player.ID, questionID, points, correct)
+	gs.announceScores(playerSession)
+}
+
+// announceScores broadcasts the standings after a player scores.
+func (gs *GameServer) announceScores(playerSession *session.PlayerSession) {
+	playerSession.BroadcastHighScore()
+	if playerSession.TeamMode {
+		playerSession.BroadcastTeamScores()
+	}
+}
+
 // MarkPlayerFinishedHandler updates a player's finished status and checks if all players are done.
+// The player is named by their resume token, so only they can finish for themselves.
 func (gs *GameServer) MarkPlayerFinishedHandler(c *gin.Context) {
 	var requestBody struct {
-		SessionID string `json:"sessionId"`
-		PlayerID  string `json:"playerId"`
+		SessionID   string `json:"sessionId"`
+		ResumeToken string `json:"resumeToken"`
 	}
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
@@ -162,18 +423,22 @@ func (gs *GameServer) MarkPlayerFinishedHandler(c *gin.Context) {
 		return
 	}
 
-	player, ok := session.Players[requestBody.PlayerID]
+	player, ok := gs.authenticatePlayer(c, session, requestBody.ResumeToken)
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
 		return
 	}
+	session.Lock()
 	player.Finished = true
+	session.Unlock()
+	// Players who finish without guessing no longer hold up numeric reveals.
+	session.RevealSettledGuesses()
 
-	gs.updateLeaderboard(player.ID, player.Score/10, len(session.Questions))
+	gs.updateLeaderboard(player.ID, player.Correct, len(session.Questions))
 
 	fmt.Println(gs.Leaderboard)
 
 	if session.CheckAllPlayersFinished() {
+		session.Complete()
 		session.Broadcast(map[string]interface{}{"type": "sessionComplete"})
 	}
 
@@ -246,6 +511,7 @@ func (gs *GameServer) EndGameHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "Game ended successfully.",
 		"finalScore": session.Score,
+		"breakdown":  session.Breakdown(),
 	})
 }
 
@@ -266,6 +532,7 @@ func (gs *GameServer) FinalScoresHandler(c *gin.Context) {
 		scores = append(scores, map[string]interface{}{
 			"playerName": player.Name,
 			"score":      player.Score,
+			"team":       player.Team,
 		})
 		if player.Score > highScore {
 			highScore = player.Score
@@ -275,18 +542,46 @@ func (gs *GameServer) FinalScoresHandler(c *gin.Context) {
 		}
 	}
 
-	// fmt.Printf("Scores: %v, Winners: %v, High Score: %d\n", scores, winners, highScore)
-	c.JSON(http.StatusOK, gin.H{
+	// Modes with their own ranking, such as elimination placements, replace score-based winners.
+	if modeScores, modeWinners, ok := modeResults(session); ok {
+		scores, winners = modeScores, modeWinners
+	}
+
+	response := gin.H{
 		"scores":    scores,
 		"winners":   winners,
 		"highScore": highScore,
-	})
+	}
+
+	// In team mode, rank the teams; tie-breaks are applied by TeamStandings.
+	if session.TeamMode {
+		teams := session.TeamStandings()
+		winningTeams := []string{}
+		for _, team := range teams {
+			if team.Rank == 1 {
+				winningTeams = append(winningTeams, team.Name)
+			}
+		}
+		response["teams"] = teams
+		response["winningTeams"] = winningTeams
+	}
+
+	// Advance the winner if this session was a tournament match.
+	gs.recordMatchResult(session)
+
+	// fmt.Printf("Scores: %v, Winners: %v, High Score: %d\n", scores, winners, highScore)
+	c.JSON(http.StatusOK, response)
 }
 
 // retrieveSession retrieves a session by its unique ID
 func (gs *GameServer) retrieveSession(c *gin.Context, sessionID string) (*session.PlayerSession, bool) {
 	session, exists := gs.Store.GetSession(sessionID)
 	if !exists {
+		// Point the caller at the node holding the session's state, if there is one.
+		if owner, err := gs.Store.Owner(sessionID); err == nil && owner != "" {
+			c.JSON(http.StatusMisdirectedRequest, gin.H{"error": "Session is owned by another node", "owner": owner})
+			return nil, false
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
 		return nil, false
 	}
@@ -295,6 +590,14 @@ func (gs *GameServer) retrieveSession(c *gin.Context, sessionID string) (*sessio
 
 // Websocket Integration
 
+// wsClient tracks the state of a single WebSocket connection.
+type wsClient struct {
+	conn       *websocket.Conn
+	subscriber *session.WebSocketSubscriber // Event subscriber wrapping conn.
+	session    *session.PlayerSession       // Session the connection joined, if any.
+	playerID   string                       // Player the connection is bound to, if any.
+}
+
 // WebSocketEndpoint upgrades an HTTP connection to a WebSocket connection and handles incoming WebSocket messages.
 func (gs *GameServer) WebSocketEndpoint(c *gin.Context) {
 	// Upgrade HTTP connection to WebSocket protocol.
@@ -304,7 +607,11 @@ func (gs *GameServer) WebSocketEndpoint(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upgrade to WebSocket"})
 		return
 	}
+	client := &wsClient{conn: conn, subscriber: session.NewWebSocketSubscriber(conn)}
 	defer func() {
+		if client.session != nil {
+			client.session.Unsubscribe(client.subscriber, gs.ReconnectGrace)
+		}
 		err := conn.Close()
 		if err != nil {
 			log.Printf("Error closing WebSocket connection: %v", err)
@@ -320,12 +627,12 @@ func (gs *GameServer) WebSocketEndpoint(c *gin.Context) {
 			log.Printf("Error reading WebSocket message: %v", err)
 			break
 		}
-		gs.processWebSocketMessage(msg, conn)
+		gs.processWebSocketMessage(msg, client)
 	}
 }
 
 // processWebSocketMessage unmarshals and processes a single WebSocket message.
-func (gs *GameServer) processWebSocketMessage(msg []byte, conn *websocket.Conn) {
+func (gs *GameServer) processWebSocketMessage(msg []byte, client *wsClient) {
 	var message map[string]interface{}
 	if err := json.Unmarshal(msg, &message); err != nil {
 		log.Printf("Error unmarshalling WebSocket message: %v", err)
@@ -335,7 +642,23 @@ func (gs *GameServer) processWebSocketMessage(msg []byte, conn *websocket.Conn)
 	if action, ok := message["action"].(string); ok {
 		switch action {
 		case "joinSession":
-			gs.handleJoinSession(message, conn)
+			gs.handleJoinSession(message, client)
+		case "resumeSession":
+			gs.handleResumeSession(message, client)
+		case "chat":
+			gs.handleChat(message, client)
+		case "react":
+			gs.handleReaction(message, client)
+		case "muteChat":
+			gs.handleMuteChat(message, client)
+		case "setChatEnabled":
+			gs.handleSetChatEnabled(message, client)
+		case "buzz":
+			gs.handleBuzz(message, client)
+		case "pause", "resume", "skipQuestion":
+			gs.handleHostControl(action, client)
+		case "reviewAnswer":
+			gs.handleReviewAnswer(message, client)
 		default:
 			log.Printf("Unhandled action type: %s", action)
 		}
@@ -344,22 +667,96 @@ func (gs *GameServer) processWebSocketMessage(msg []byte, conn *websocket.Conn)
 	}
 }
 
-// handleJoinSession processes a "joinSession" action from a WebSocket message.
-func (gs *GameServer) handleJoinSession(message map[string]interface{}, conn *websocket.Conn) {
+// handleJoinSession processes a "joinSession" action from a WebSocket message. A player who
+// joined over HTTP sends their "resumeToken" too, binding the connection to them so they get
+// events live. Without it the connection only watches the session and gets events after the
+// spectator delay. "spectate": true joins as a spectator instead.
+func (gs *GameServer) handleJoinSession(message map[string]interface{}, client *wsClient) {
 	sessionID, ok := message["sessionId"].(string)
 	if !ok {
 		log.Println("WebSocket joinSession message does not contain 'sessionId'")
 		return
 	}
 
-	session, exists := gs.Store.GetSession(sessionID)
+	session, exists := gs.Store.AttachSession(sessionID)
 	if !exists {
 		log.Printf("Session not found: %s", sessionID)
 		return
 	}
 
-	session.AddConnection(conn)
+	if spectate, _ := message["spectate"].(bool); spectate {
+		gs.attachSpectator(client, session)
+		return
+	}
+
+	gs.attachClient(client, session)
+	if token, _ := message["resumeToken"].(string); token != "" {
+		player, ok := session.ResumePlayer(token)
+		if !ok {
+			log.Printf("Invalid resume token for session: %s", sessionID)
+			return
+		}
+		session.BindSubscriber(client.subscriber, player.ID)
+		client.playerID = player.ID
+	}
 	log.Printf("Player joined session: %s", sessionID)
 
+	session.SendTo(client.subscriber, map[string]interface{}{"type": "chatHistory", "messages": session.Chat.History()})
+
 	session.BroadcastPlayerCount()
 }
+
+// handleResumeSession processes a "resumeSession" action, rebinding the connection to the
+// player owning the resume token and replaying the session state to them.
+func (gs *GameServer) handleResumeSession(message map[string]interface{}, client *wsClient) {
+	sessionID, _ := message["sessionId"].(string)
+	token, _ := message["resumeToken"].(string)
+	if sessionID == "" || token == "" {
+		log.Println("WebSocket resumeSession message requires 'sessionId' and 'resumeToken'")
+		return
+	}
+
+	session, exists := gs.Store.GetSession(sessionID)
+	if !exists {
+		log.Printf("Session not found: %s", sessionID)
+		return
+	}
+
+	player, ok := session.ResumePlayer(token)
+	if !ok {
+		log.Printf("Invalid resume token for session: %s", sessionID)
+		return
+	}
+
+	gs.attachClient(client, session)
+	session.BindSubscriber(client.subscriber, player.ID)
+	client.playerID = player.ID
+	log.Printf("Player %s resumed session: %s", player.ID, sessionID)
+
+	session.SendTo(client.subscriber, session.Snapshot(player.ID))
+}
+
+// attachClient registers the connection with a session, detaching it from any previous one.
+func (gs *GameServer) attachClient(client *wsClient, session *session.PlayerSession) {
+	if client.session != nil && client.session != session {
+		client.session.Unsubscribe(client.subscriber, gs.ReconnectGrace)
+	}
+	client.session = session
+	client.playerID = ""
+	session.Subscribe(client.subscriber)
+}
+
+// attachSpectator registers the connection as a spectator, who follows the game without
+// playing, and sends them the current state through their possibly delayed stream.
+func (gs *GameServer) attachSpectator(client *wsClient, session *session.PlayerSession) {
+	if client.session != nil {
+		client.session.Unsubscribe(client.subscriber, gs.ReconnectGrace)
+	}
+	client.session = session
+	client.playerID = ""
+	stream := session.SubscribeSpectator(client.subscriber)
+	log.Printf("Spectator joined session: %s", session.ID)
+
+	session.SendTo(stream, session.Snapshot(""))
+	session.BroadcastSpectatorCount()
+}
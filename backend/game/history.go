@@ -0,0 +1,66 @@
+package game
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnsweredQuestionsHandler lists the questions a user has answered that new games avoid,
+// oldest first, by their question keys. Only the user may list them.
+func (gs *GameServer) AnsweredQuestionsHandler(c *gin.Context) {
+	userID, ok := gs.historyOwner(c)
+	if !ok {
+		return
+	}
+	entries := gs.Store.History.Entries(userID)
+	c.JSON(http.StatusOK, gin.H{"questions": entries, "total": len(entries)})
+}
+
+// ResetAnsweredQuestionsHandler forgets the questions a user has answered, so any may be
+// asked of them again. Only the user may reset them.
+func (gs *GameServer) ResetAnsweredQuestionsHandler(c *gin.Context) {
+	userID, ok := gs.historyOwner(c)
+	if !ok {
+		return
+	}
+	if err := gs.Store.History.Reset(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset question history"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Question history reset."})
+}
+
+// historyOwner returns the user whose history a request names, once the user token it is
+// sent with as a bearer token proves the request comes from them. Otherwise it writes an
+// error response.
+func (gs *GameServer) historyOwner(c *gin.Context) (string, bool) {
+	if gs.Store.History == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question history is not kept"})
+		return "", false
+	}
+	token, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	userID, ok := gs.verifyUser(c, token)
+	if !ok {
+		return "", false
+	}
+	if userID != c.Param("userId") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "userToken belongs to another user"})
+		return "", false
+	}
+	return userID, true
+}
+
+// setSoloUser remembers the user playing a single-player session, so the questions they
+// answer go in their history. An empty user ID leaves the player unknown.
+func (gs *GameServer) setSoloUser(sessionID, userID string) {
+	if userID == "" {
+		return
+	}
+	if playerSession, exists := gs.Store.GetSession(sessionID); exists {
+		playerSession.Lock()
+		playerSession.SoloUserID = userID
+		playerSession.Unlock()
+	}
+}
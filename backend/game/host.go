@@ -0,0 +1,99 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gclluch/TriviaApp-ReactGo/session"
+	"github.com/gin-gonic/gin"
+)
+
+// errUnknownControl is returned for host control actions other than pause, resume and skipQuestion.
+var errUnknownControl = errors.New("unknown control action")
+
+// HostControlHandler lets the host pause, resume or skip the current question over HTTP, for
+// clients following the game over Server-Sent Events. Skipping works in elimination and
+// buzzer games; classic games have no shared question to skip. The host is identified by their resume
+// token, since player IDs are visible to everyone in the session.
+func (gs *GameServer) HostControlHandler(c *gin.Context) {
+	var requestBody struct {
+		ResumeToken string `json:"resumeToken"`
+		Action      string `json:"action"` // "pause", "resume" or "skipQuestion"
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	playerSession, ok := gs.authenticateHost(c, requestBody.ResumeToken)
+	if !ok {
+		return
+	}
+
+	switch err := hostControl(playerSession, requestBody.Action); {
+	case errors.Is(err, errUnknownControl), errors.Is(err, session.ErrSkipUnsupported):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, gin.H{"action": requestBody.Action, "paused": playerSession.IsPaused()})
+	}
+}
+
+// authenticateHost looks up the session named in the path and checks that the resume token
+// belongs to its host, writing an error response if not.
+func (gs *GameServer) authenticateHost(c *gin.Context, resumeToken string) (*session.PlayerSession, bool) {
+	playerSession, ok := gs.retrieveSession(c, c.Param("sessionId"))
+	if !ok {
+		return nil, false
+	}
+
+	playerSession.Lock()
+	playerID := playerSession.ResumeTokens[resumeToken]
+	isHost := playerID != "" && playerSession.HostID == playerID
+	playerSession.Unlock()
+	if !isHost {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the host can do that"})
+		return nil, false
+	}
+	return playerSession, true
+}
+
+// authenticatePlayer returns the player of a session owning the resume token, writing an
+// error response if none does. Player IDs are public, so requests acting for a player prove
+// it with the token only that player was given.
+func (gs *GameServer) authenticatePlayer(c *gin.Context, playerSession *session.PlayerSession, resumeToken string) (*models.Player, bool) {
+	playerSession.Lock()
+	player, exists := playerSession.Players[playerSession.ResumeTokens[resumeToken]]
+	playerSession.Unlock()
+	if resumeToken == "" || !exists {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid resume token"})
+		return nil, false
+	}
+	return player, true
+}
+
+// handleHostControl processes a host's "pause", "resume" or "skipQuestion" WebSocket action.
+func (gs *GameServer) handleHostControl(action string, client *wsClient) {
+	if _, ok := gs.hostPlayer(action, client); !ok {
+		return
+	}
+	if err := hostControl(client.session, action); err != nil {
+		gs.rejectAction(client, action, err.Error())
+	}
+}
+
+// hostControl applies a host control action. The session broadcasts the resulting
+// "paused", "resumed" or "questionSkipped" event.
+func hostControl(playerSession *session.PlayerSession, action string) error {
+	switch action {
+	case "pause":
+		return playerSession.Pause()
+	case "resume":
+		return playerSession.Resume()
+	case "skipQuestion":
+		return playerSession.SkipCurrentQuestion()
+	}
+	return errUnknownControl
+}
@@ -0,0 +1,30 @@
+package game
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IssueUserHandler gives a new player their user ID and the token proving it. Clients keep
+// both across games and send the token wherever a request acts for the user. Each client
+// address is issued only a few IDs per window.
+func (gs *GameServer) IssueUserHandler(c *gin.Context) {
+	userID, token, err := gs.Identity.IssueTo(c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"userId": userID, "userToken": token})
+}
+
+// verifyUser returns the user ID a user token proves, writing an error response if it
+// proves none.
+func (gs *GameServer) verifyUser(c *gin.Context, token string) (string, bool) {
+	userID, err := gs.Identity.Verify(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return userID, true
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gclluch/TriviaApp-ReactGo/services"
+	"github.com/gclluch/TriviaApp-ReactGo/session"
+	"github.com/gin-gonic/gin"
+)
+
+// LifelineHandler spends a lifeline on a single-player question. A 50/50 returns the two
+// options ruled out, a skip returns the replacement question and ask-the-audience returns
+// how earlier players answered, as percentages per option.
+func (gs *GameServer) LifelineHandler(c *gin.Context) {
+	var requestBody struct {
+		SessionID  string `json:"sessionId"`
+		QuestionID string `json:"questionId"`
+		Lifeline   string `json:"lifeline"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	lifeline := session.Lifeline(requestBody.Lifeline)
+	if !session.ValidLifeline(lifeline) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown lifeline"})
+		return
+	}
+
+	playerSession, ok := gs.retrieveSession(c, requestBody.SessionID)
+	if !ok {
+		return
+	}
+
+	response := gin.H{"lifeline": lifeline, "questionId": requestBody.QuestionID}
+	var err error
+	switch lifeline {
+	case session.LifelineFiftyFifty:
+		var removed []int
+		removed, err = playerSession.FiftyFifty(requestBody.QuestionID)
+		response["removed"] = removed
+	case session.LifelineSkip:
+		var replacement models.Question
+		replacement, err = gs.skipQuestion(playerSession, requestBody.QuestionID)
+		response["question"] = playerSession.PublicQuestion(replacement)
+	case session.LifelineAudience:
+		var question models.Question
+		question, err = playerSession.AskTheAudience(requestBody.QuestionID)
+		response["distribution"], response["responses"] = gs.Store.Stats.Distribution(question)
+	}
+
+	switch {
+	case errors.Is(err, session.ErrQuestionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+	case errors.Is(err, errQuestionSource):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		response["remaining"] = playerSession.RemainingLifelines()
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// errQuestionSource is returned when no replacement question could be fetched for a skip.
+var errQuestionSource = errors.New("could not fetch a replacement question")
+
+// skipQuestion fetches a fresh question of the same type from the provider and swaps it in,
+// drawn from the same category and difficulty when the question's round has them, and avoiding
+// questions the player has answered in earlier games. The lifeline is checked first so a
+// refused skip does not cost a fetch.
+func (gs *GameServer) skipQuestion(playerSession *session.PlayerSession, questionID string) (models.Question, error) {
+	if err := playerSession.CheckLifeline(questionID, session.LifelineSkip); err != nil {
+		return models.Question{}, err
+	}
+
+	query := services.Query{Amount: 1, Exclude: gs.Store.SeenQuestions(playerSession.UserIDs())}
+	if question, exists := findQuestion(playerSession.Questions, questionID); exists {
+		query.Type = question.QuestionType()
+	}
+	if round, ok := playerSession.RoundOf(questionID); ok {
+		query.Category, query.Difficulty = round.Category, round.Difficulty
+	}
+	questions, err := gs.Store.Questions.FetchQuestions(query)
+	if err != nil || len(questions) == 0 {
+		log.Printf("Failed to fetch a replacement question for session %s: %v", playerSession.ID, err)
+		return models.Question{}, errQuestionSource
+	}
+	return playerSession.SkipQuestion(questionID, questions[0])
+}
+
+// recordAnswer adds an answer to the statistics ask-the-audience draws on.
+func (gs *GameServer) recordAnswer(questions []models.Question, submission models.AnswerSubmission) {
+	if question, exists := findQuestion(questions, submission.QuestionID); exists && question.SingleAnswer() {
+		gs.Store.Stats.Record(question, submission.Answer)
+	}
+}
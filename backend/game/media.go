@@ -0,0 +1,46 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/media"
+	"github.com/gin-gonic/gin"
+)
+
+// MediaHandler serves a question's image or sound clip to a client holding a URL for it.
+func (gs *GameServer) MediaHandler(c *gin.Context) {
+	library := gs.Store.Media
+	if library == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+
+	key, expires, err := library.Resolve(c.Param("token"))
+	switch {
+	case errors.Is(err, media.ErrExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := library.Store.Open(key)
+	switch {
+	case errors.Is(err, media.ErrNotFound), errors.Is(err, media.ErrInvalidKey):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open media"})
+		return
+	}
+	defer file.Close()
+
+	// Caches may keep the file only as long as the URL is valid.
+	maxAge := max(0, int64(time.Until(expires)/time.Second))
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+	http.ServeContent(c.Writer, c.Request, key, time.Time{}, file)
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gclluch/TriviaApp-ReactGo/session"
+	"github.com/gin-gonic/gin"
+)
+
+// answerForMode applies the answer rules of game modes other than classic. It reports
+// whether it wrote a response; classic answers fall through to AnswerHandler.
+func (gs *GameServer) answerForMode(c *gin.Context, playerSession *session.PlayerSession, player *models.Player, submission models.AnswerSubmission, correct bool) bool {
+	switch playerSession.Mode {
+	case session.ModeBuzzer:
+		gs.answerBuzzer(c, playerSession, player, submission, correct)
+		return true
+	case session.ModeElimination:
+		gs.answerPaced(c, playerSession, player, submission, correct)
+		return true
+	}
+	return false
+}
+
+// runSession drives a session from its pre-game countdown through any server-paced play.
+// It stops early if the session is closed.
+func (gs *GameServer) runSession(playerSession *session.PlayerSession) {
+	if err := playerSession.StartCountdown(5); err != nil {
+		log.Printf("Session %s closed during its countdown", playerSession.ID)
+		return
+	}
+
+	switch playerSession.Mode {
+	case session.ModeElimination:
+		if err := playerSession.RunElimination(); err != nil {
+			log.Printf("Session %s closed during play", playerSession.ID)
+		}
+	}
+}
+
+// answerPaced records an answer to the question the server is asking. Correctness is
+// withheld until the server reveals the answer to everyone.
+func (gs *GameServer) answerPaced(c *gin.Context, playerSession *session.PlayerSession, player *models.Player, submission models.AnswerSubmission, correct bool) {
+	if err := playerSession.SubmitPacedAnswer(player.ID, submission.QuestionID, correct); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	gs.recordAnswer(playerSession.Questions, submission)
+	c.JSON(http.StatusOK, gin.H{"accepted": true, "currentScore": player.Score})
+}
+
+// modeResults ranks players for modes that do not rank by score alone. It reports false
+// for modes where FinalScoresHandler's score ranking applies.
+func modeResults(playerSession *session.PlayerSession) ([]map[string]interface{}, []string, bool) {
+	switch playerSession.Mode {
+	case session.ModeElimination:
+		scores, winners := eliminationResults(playerSession)
+		return scores, winners, true
+	}
+	return nil, nil, false
+}
+
+// eliminationResults lists players by placement, set by elimination order. Players who only
+// watched have no placement and are listed last.
+func eliminationResults(playerSession *session.PlayerSession) ([]map[string]interface{}, []string) {
+	playerSession.Lock()
+	players := make([]*models.Player, 0, len(playerSession.Players))
+	for _, player := range playerSession.Players {
+		players = append(players, player)
+	}
+	playerSession.Unlock()
+
+	sort.SliceStable(players, func(i, j int) bool {
+		a, b := players[i].Placement, players[j].Placement
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		return a < b
+	})
+
+	scores := make([]map[string]interface{}, 0, len(players))
+	winners := []string{}
+	for _, player := range players {
+		scores = append(scores, map[string]interface{}{
+			"playerName": player.Name,
+			"score":      player.Score,
+			"team":       player.Team,
+			"placement":  player.Placement,
+			"eliminated": player.Eliminated,
+		})
+		if player.Placement == 1 {
+			winners = append(winners, player.Name)
+		}
+	}
+	return scores, winners
+}
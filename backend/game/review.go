@@ -0,0 +1,78 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gclluch/TriviaApp-ReactGo/session"
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewAnswerHandler lets the host accept or reject a borderline free-text answer over HTTP.
+func (gs *GameServer) ReviewAnswerHandler(c *gin.Context) {
+	var requestBody struct {
+		ResumeToken string `json:"resumeToken"`
+		ReviewID    string `json:"reviewId"`
+		Accept      bool   `json:"accept"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	playerSession, ok := gs.authenticateHost(c, requestBody.ResumeToken)
+	if !ok {
+		return
+	}
+
+	awarded, err := gs.resolveReview(playerSession, requestBody.ReviewID, requestBody.Accept)
+	switch {
+	case errors.Is(err, session.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, gin.H{"reviewId": requestBody.ReviewID, "accepted": requestBody.Accept, "awarded": awarded})
+	}
+}
+
+// handleReviewAnswer processes a host's "reviewAnswer" WebSocket action.
+func (gs *GameServer) handleReviewAnswer(message map[string]interface{}, client *wsClient) {
+	if _, ok := gs.hostPlayer("reviewAnswer", client); !ok {
+		return
+	}
+
+	reviewID, _ := message["reviewId"].(string)
+	accept, _ := message["accept"].(bool)
+	if _, err := gs.resolveReview(client.session, reviewID, accept); err != nil {
+		gs.rejectAction(client, "reviewAnswer", err.Error())
+	}
+}
+
+// resolveReview applies the host's decision on a borderline answer. An accepted answer
+// scores like any correct answer, so it only earns points if nobody has scored on the
+// question since. The player is told the outcome either way; it reports whether points were awarded.
+func (gs *GameServer) resolveReview(playerSession *session.PlayerSession, reviewID string, accept bool) (bool, error) {
+	review, err := playerSession.ResolveReview(reviewID)
+	if err != nil {
+		return false, err
+	}
+
+	playerSession.Lock()
+	player, exists := playerSession.Players[review.PlayerID]
+	playerSession.Unlock()
+	if !exists {
+		return false, session.ErrPlayerNotFound
+	}
+
+	awarded := accept && gs.scoreAnswer(playerSession, player, review.QuestionID)
+	playerSession.SendToPlayer(player.ID, map[string]interface{}{
+		"type":         "answerReviewed",
+		"reviewId":     review.ID,
+		"questionId":   review.QuestionID,
+		"accepted":     accept,
+		"correct":      awarded,
+		"currentScore": player.Score,
+	})
+	return awarded, nil
+}
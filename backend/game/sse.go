@@ -0,0 +1,95 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/session"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	sseBufferSize        = 64               // Events queued per SSE client before it is dropped.
+	sseKeepAliveInterval = 15 * time.Second // Comment frames that keep idle proxies from closing the stream.
+)
+
+// EventStreamHandler streams session events over Server-Sent Events for clients that cannot
+// open a WebSocket. It carries the same events as the WebSocket endpoint. An optional
+// "resumeToken" query parameter binds the stream to a player and replays a state snapshot;
+// without one the stream only watches, and its events lag by the spectator delay.
+// "spectate=true" opens the stream as a spectator instead.
+func (gs *GameServer) EventStreamHandler(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+	playerSession, ok := gs.Store.AttachSession(sessionID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	subscriber := session.NewStreamSubscriber(sseBufferSize)
+	spectating := c.Query("spectate") == "true"
+	if spectating {
+		stream := playerSession.SubscribeSpectator(subscriber)
+		playerSession.SendTo(stream, playerSession.Snapshot(""))
+	} else {
+		playerSession.Subscribe(subscriber)
+	}
+	defer func() {
+		playerSession.Unsubscribe(subscriber, gs.ReconnectGrace)
+		subscriber.Close()
+	}()
+
+	if token := c.Query("resumeToken"); token != "" && !spectating {
+		if playerSession.Relay {
+			c.JSON(http.StatusMisdirectedRequest, gin.H{"error": "Session is owned by another node"})
+			return
+		}
+		player, ok := playerSession.ResumePlayer(token)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+			return
+		}
+		playerSession.BindSubscriber(subscriber, player.ID)
+		playerSession.SendTo(subscriber, playerSession.Snapshot(player.ID))
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable response buffering in nginx-style proxies.
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	log.Printf("SSE stream opened for session: %s", sessionID)
+	if spectating {
+		playerSession.BroadcastSpectatorCount()
+	} else {
+		playerSession.BroadcastPlayerCount()
+	}
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			log.Printf("SSE stream closed for session: %s", sessionID)
+			return
+		case message, open := <-subscriber.Messages():
+			if !open {
+				return
+			}
+			if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", message); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gclluch/TriviaApp-ReactGo/session"
+	"github.com/gclluch/TriviaApp-ReactGo/store"
+	"github.com/gclluch/TriviaApp-ReactGo/tournament"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTournamentHandler opens a tournament for registration. Options apply to every match session.
+func (gs *GameServer) CreateTournamentHandler(c *gin.Context) {
+	var requestBody struct {
+		Name    string             `json:"name"`
+		Format  string             `json:"format"` // "bracket" or "swiss"
+		Rounds  int                `json:"rounds"` // Swiss rounds; 0 picks enough to separate the players.
+		Options models.GameOptions `json:"options"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if requestBody.Options.NumQuestions == 0 {
+		requestBody.Options.NumQuestions = 10
+	}
+	if err := store.ValidateOptions(&requestBody.Options); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := gs.Tournaments.Create(requestBody.Name, tournament.Format(requestBody.Format), requestBody.Rounds, requestBody.Options)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tournament": created})
+}
+
+// RegisterTournamentHandler signs a player up for a tournament that has not started. The
+// response carries the entrant's secret token, which they join their matches with.
+func (gs *GameServer) RegisterTournamentHandler(c *gin.Context) {
+	var requestBody struct {
+		PlayerName string `json:"playerName"`
+		Rating     int    `json:"rating"` // Higher ratings get better seeds.
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil || requestBody.PlayerName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	entrant, token, err := gs.Tournaments.Register(c.Param("tournamentId"), requestBody.PlayerName, requestBody.Rating)
+	if err != nil {
+		gs.tournamentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entrant": entrant, "entrantToken": token})
+}
+
+// StartTournamentHandler seeds a tournament and creates its first round of match sessions.
+func (gs *GameServer) StartTournamentHandler(c *gin.Context) {
+	tournamentID := c.Param("tournamentId")
+	if err := gs.Tournaments.Start(tournamentID); err != nil {
+		gs.tournamentError(c, err)
+		return
+	}
+	gs.GetTournamentHandler(c)
+}
+
+// GetTournamentHandler returns a tournament's entrants, rounds and match results.
+func (gs *GameServer) GetTournamentHandler(c *gin.Context) {
+	found, err := gs.Tournaments.Get(c.Param("tournamentId"))
+	if err != nil {
+		gs.tournamentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tournament": found})
+}
+
+// seatEntrant claims a tournament entrant's place in a match session before they join it.
+// It reports whether the session is a match and, if it is, whether the entrant may join;
+// when they may not, the error response has been written.
+func (gs *GameServer) seatEntrant(c *gin.Context, sessionID, entrantID, token string) (string, bool, bool) {
+	name, err := gs.Tournaments.Seat(sessionID, entrantID, token)
+	switch {
+	case errors.Is(err, tournament.ErrNotMatchSession):
+		return "", false, true
+	case errors.Is(err, tournament.ErrNotInMatch), errors.Is(err, tournament.ErrInvalidEntrant):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return "", true, false
+	case err != nil:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return "", true, false
+	}
+	return name, true, true
+}
+
+// recordMatchResult reports a completed session's scores to its tournament, if it is a match.
+func (gs *GameServer) recordMatchResult(playerSession *session.PlayerSession) {
+	playerSession.Lock()
+	if playerSession.Phase != session.PhaseComplete {
+		playerSession.Unlock()
+		return
+	}
+	scores := make(map[string]int, len(playerSession.Players))
+	for playerID, player := range playerSession.Players {
+		scores[playerID] = player.Score
+	}
+	playerSession.Unlock()
+
+	gs.Tournaments.RecordResult(playerSession.ID, scores)
+}
+
+// tournamentError maps a tournament error to its HTTP status.
+func (gs *GameServer) tournamentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, tournament.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gorilla/websocket"
+)
+
+// dialSession connects to the WebSocket endpoint and sends a joinSession action.
+func dialSession(t *testing.T, server *httptest.Server, join map[string]interface{}) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	join["action"] = "joinSession"
+	if err := conn.WriteJSON(join); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	return conn
+}
+
+// awaitEvent reads events until one of the given type arrives, reporting whether it came
+// within the timeout.
+func awaitEvent(conn *websocket.Conn, eventType string, timeout time.Duration) bool {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		var event map[string]interface{}
+		if err := conn.ReadJSON(&event); err != nil {
+			return false
+		}
+		if event["type"] == eventType {
+			return true
+		}
+	}
+}
+
+func TestJoinedPlayersSkipTheSpectatorDelay(t *testing.T) {
+	questions := []models.Question{{ID: "1", QuestionText: "Q", Options: []string{"A", "B"}, CorrectIndex: 0}}
+	_, router, playerSession := newTestGame(t, models.GameOptions{SpectatorDelay: 5}, questions)
+	server := httptest.NewServer(router)
+	defer server.Close()
+	sessionID := playerSession.ID
+	player := playerSession.AddPlayer()
+
+	joined := dialSession(t, server, map[string]interface{}{"sessionId": sessionID, "resumeToken": player.ResumeToken})
+	watcher := dialSession(t, server, map[string]interface{}{"sessionId": sessionID})
+	if !awaitEvent(joined, "chatHistory", time.Second) {
+		t.Fatal("Expected the joined player to get the chat history")
+	}
+	// The watcher's own history is held back too, so give its join a moment to register.
+	time.Sleep(50 * time.Millisecond)
+
+	playerSession.BroadcastPlayerCount()
+	if !awaitEvent(joined, "playerCount", time.Second) {
+		t.Error("The joined player's events should not wait out the spectator delay")
+	}
+	if awaitEvent(watcher, "playerCount", 300*time.Millisecond) {
+		t.Error("A connection without a resume token should get events after the spectator delay")
+	}
+}
@@ -0,0 +1,328 @@
+// Package grading decides whether submitted answers are right, whatever the question type.
+package grading
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultTolerance is how many edits a free-text answer may be off by when none is configured.
+const DefaultTolerance = 2
+
+var (
+	ErrNotAnOption     = errors.New("answer is not one of the question's options")
+	ErrMissingAnswer   = errors.New("answer is missing")
+	ErrIncompleteOrder = errors.New("answer must place every option exactly once")
+)
+
+// Verdict is the outcome of grading an answer.
+type Verdict string
+
+const (
+	Correct    Verdict = "correct"    // Matches an accepted answer within the tolerance.
+	Borderline Verdict = "borderline" // Close to an accepted answer, but past the tolerance; worth a human look.
+	Wrong      Verdict = "wrong"      // Not close to any accepted answer.
+	Ranked     Verdict = "ranked"     // A guess at a closest-answer question, scored against the other guesses.
+	Partial    Verdict = "partial"    // Partly right, earning a share of the points.
+)
+
+// Grade is the outcome of grading an answer, with the share of the question's points it earns.
+type Grade struct {
+	Verdict Verdict
+	Credit  float64 // From 0 to 1; for ranked guesses, the credit by relative error.
+}
+
+// articles are dropped from the start of answers, so "The Beatles" matches "Beatles".
+var articles = []string{"the ", "a ", "an "}
+
+// Normalize reduces an answer to the form answers are compared in: lower case, without
+// diacritics, punctuation or a leading article, and with runs of whitespace collapsed.
+func Normalize(text string) string {
+	var builder strings.Builder
+	space := false
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks left over from decomposing accented letters.
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && builder.Len() > 0 {
+				builder.WriteByte(' ')
+			}
+			space = false
+			builder.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '/':
+			space = true
+		}
+	}
+
+	normalized := builder.String()
+	for _, article := range articles {
+		if trimmed := strings.TrimPrefix(normalized, article); trimmed != normalized && trimmed != "" {
+			return trimmed
+		}
+	}
+	return normalized
+}
+
+// Distance returns the Levenshtein edit distance between two strings, counted in runes.
+func Distance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
+
+// MatchText compares a free-text answer against the accepted answers. An answer is correct
+// within tolerance edits of an accepted answer, though never more than a quarter of its
+// length, so short answers must be spelled closely. Answers a little further off are borderline.
+// An accepted answer with digits in it, such as a year, must be matched exactly, since one
+// edit there makes a different answer rather than a typo.
+func MatchText(text string, accepted []string, tolerance int) Verdict {
+	answer := Normalize(text)
+	if answer == "" {
+		return Wrong
+	}
+
+	verdict := Wrong
+	for _, candidate := range accepted {
+		expected := Normalize(candidate)
+		if expected == "" {
+			continue
+		}
+		if strings.IndexFunc(expected, unicode.IsDigit) >= 0 {
+			if answer == expected {
+				return Correct
+			}
+			continue
+		}
+		allowed := min(tolerance, len([]rune(expected))/4)
+		switch distance := Distance(answer, expected); {
+		case distance <= allowed:
+			return Correct
+		case distance <= 2*allowed+1:
+			verdict = Borderline
+		}
+	}
+	return verdict
+}
+
+// Check grades a submission to a question by the question's type: an option index for
+// multiple choice and true/false questions, typed text for free-text questions, a number
+// for closest-answer questions, and selections for multi-select and ordering questions. It
+// returns an error if the submission does not answer the question at all.
+func Check(question models.Question, submission models.AnswerSubmission, tolerance int) (Grade, error) {
+	switch question.QuestionType() {
+	case models.QuestionFreeText:
+		if strings.TrimSpace(submission.Text) == "" {
+			return Grade{Verdict: Wrong}, ErrMissingAnswer
+		}
+		return verdictGrade(MatchText(submission.Text, question.Answers, tolerance)), nil
+	case models.QuestionNumeric:
+		if submission.Number == nil || math.IsNaN(*submission.Number) || math.IsInf(*submission.Number, 0) {
+			return Grade{Verdict: Wrong}, ErrMissingAnswer
+		}
+		return Grade{Verdict: Ranked, Credit: RelativeCredit(question.NumericValue(), *submission.Number)}, nil
+	case models.QuestionMultiSelect:
+		if len(submission.Selections) == 0 {
+			return Grade{Verdict: Wrong}, ErrMissingAnswer
+		}
+		if !distinctOptions(question, submission.Selections) {
+			return Grade{Verdict: Wrong}, ErrNotAnOption
+		}
+		return creditGrade(SelectionCredit(question.CorrectIndexes, submission.Selections)), nil
+	case models.QuestionOrdering:
+		if len(submission.Selections) != len(question.Options) || !distinctOptions(question, submission.Selections) {
+			return Grade{Verdict: Wrong}, ErrIncompleteOrder
+		}
+		return creditGrade(OrderCredit(question.CorrectOrder, submission.Selections)), nil
+	default:
+		if !question.HasOption(submission.Answer) {
+			return Grade{Verdict: Wrong}, ErrNotAnOption
+		}
+		if submission.Answer == question.CorrectIndex {
+			return verdictGrade(Correct), nil
+		}
+		return verdictGrade(Wrong), nil
+	}
+}
+
+// creditGrade grades an answer earning the given share of the points.
+func creditGrade(credit float64) Grade {
+	switch {
+	case credit >= 1:
+		return Grade{Verdict: Correct, Credit: 1}
+	case credit > 0:
+		return Grade{Verdict: Partial, Credit: credit}
+	}
+	return Grade{Verdict: Wrong}
+}
+
+// distinctOptions reports whether every selection is one of the question's options, picked once.
+func distinctOptions(question models.Question, selections []int) bool {
+	seen := make(map[int]bool, len(selections))
+	for _, index := range selections {
+		if !question.HasOption(index) || seen[index] {
+			return false
+		}
+		seen[index] = true
+	}
+	return true
+}
+
+// SelectionCredit returns the share of a multi-select question's points earned by the
+// selections: each correct option picked earns a share, each wrong option picked takes one
+// back, and the credit never drops below zero.
+func SelectionCredit(correct, selections []int) float64 {
+	if len(correct) == 0 {
+		return 0
+	}
+	isCorrect := make(map[int]bool, len(correct))
+	for _, index := range correct {
+		isCorrect[index] = true
+	}
+
+	hits := 0
+	for _, index := range selections {
+		if isCorrect[index] {
+			hits++
+		} else {
+			hits--
+		}
+	}
+	return math.Max(0, float64(hits)/float64(len(correct)))
+}
+
+// OrderCredit returns the share of an ordering question's points earned by the order given:
+// the share of option pairs placed in the right order relative to each other, so moving one
+// option out of place costs less than scrambling the lot.
+func OrderCredit(correct, order []int) float64 {
+	if len(order) != len(correct) || len(correct) == 0 {
+		return 0
+	}
+	if len(correct) == 1 {
+		return 1
+	}
+	position := make(map[int]int, len(order))
+	for i, index := range order {
+		position[index] = i
+	}
+
+	pairs, inOrder := 0, 0
+	for i := range correct {
+		for j := i + 1; j < len(correct); j++ {
+			pairs++
+			if position[correct[i]] < position[correct[j]] {
+				inOrder++
+			}
+		}
+	}
+	return float64(inOrder) / float64(pairs)
+}
+
+// PartialCredit reports whether a question's answers can be partly right. Every player earns
+// their own share of such questions, rather than only the first to answer correctly.
+func PartialCredit(question models.Question) bool {
+	switch question.QuestionType() {
+	case models.QuestionMultiSelect, models.QuestionOrdering:
+		return true
+	}
+	return false
+}
+
+// verdictGrade gives full credit to a correct verdict and none to any other.
+func verdictGrade(verdict Verdict) Grade {
+	if verdict == Correct {
+		return Grade{Verdict: verdict, Credit: 1}
+	}
+	return Grade{Verdict: verdict}
+}
+
+// NumericScoring selects how closest-answer questions award points.
+type NumericScoring string
+
+const (
+	ByRank  NumericScoring = "rank"  // The closest guess scores in full; each rank further away scores less.
+	ByError NumericScoring = "error" // Each guess scores in proportion to how close it is, relative to the true value.
+)
+
+// ValidNumericScoring reports whether scoring is a supported closest-answer rule.
+func ValidNumericScoring(scoring NumericScoring) bool {
+	switch scoring {
+	case ByRank, ByError:
+		return true
+	}
+	return false
+}
+
+// GuessResult is one player's guess at a closest-answer question, ranked against the others.
+type GuessResult struct {
+	PlayerID   string  `json:"playerId"`
+	PlayerName string  `json:"playerName"`
+	Guess      float64 `json:"guess"`
+	Distance   float64 `json:"distance"` // How far the guess is from the true value.
+	Rank       int     `json:"rank"`     // 1 for the closest; equally close guesses share a rank.
+	Points     int     `json:"points"`
+}
+
+// RelativeCredit returns the share of a question's points a guess earns under ByError: full
+// credit for the exact value, falling to none at 100% relative error.
+func RelativeCredit(value, guess float64) float64 {
+	if value == 0 {
+		if guess == 0 {
+			return 1
+		}
+		return 0
+	}
+	return math.Max(0, 1-math.Abs(guess-value)/math.Abs(value))
+}
+
+// ScoreGuesses ranks guesses by distance from the true value and awards each a share of points.
+func ScoreGuesses(value float64, guesses map[string]float64, points int, scoring NumericScoring) []GuessResult {
+	results := make([]GuessResult, 0, len(guesses))
+	for playerID, guess := range guesses {
+		results = append(results, GuessResult{PlayerID: playerID, Guess: guess, Distance: math.Abs(guess - value)})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Distance != results[j].Distance {
+			return results[i].Distance < results[j].Distance
+		}
+		return results[i].PlayerID < results[j].PlayerID
+	})
+
+	for i := range results {
+		result := &results[i]
+		if i > 0 && results[i-1].Distance == result.Distance {
+			result.Rank = results[i-1].Rank
+		} else {
+			result.Rank = i + 1
+		}
+		switch scoring {
+		case ByError:
+			result.Points = int(math.Round(float64(points) * RelativeCredit(value, result.Guess)))
+		default:
+			result.Points = int(math.Round(float64(points) * float64(len(results)-result.Rank+1) / float64(len(results))))
+		}
+	}
+	return results
+}
@@ -0,0 +1,87 @@
+package grading
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestMatchTextNormalizesAndTolerates(t *testing.T) {
+	accepted := []string{"Leonardo da Vinci", "Da Vinci"}
+	cases := []struct {
+		text    string
+		verdict Verdict
+	}{
+		{"leonardo DA VINCI!", Correct},    // Case and punctuation.
+		{"Léonardo da Vinci", Correct},     // Diacritics.
+		{"Leonrdo da Vinci", Correct},      // One typo.
+		{"the da vinci", Correct},          // Leading article.
+		{"Da Vinchy", Correct},             // Within a quarter of the length.
+		{"Leonerdo de Vinchy", Borderline}, // A little further off.
+		{"Michelangelo", Wrong},
+		{"  ", Wrong},
+	}
+	for _, tc := range cases {
+		if got := MatchText(tc.text, accepted, DefaultTolerance); got != tc.verdict {
+			t.Errorf("MatchText(%q) = %s; want %s", tc.text, got, tc.verdict)
+		}
+	}
+
+	// Short answers allow no typos, so near misses go to review.
+	if got := MatchText("Mras", []string{"Mars"}, DefaultTolerance); got != Borderline {
+		t.Errorf("Expected a transposed short answer to be borderline; got %s", got)
+	}
+
+	// Answers with digits allow no edits at all.
+	for text, verdict := range map[string]Verdict{"1984": Correct, "1985": Wrong, "Apollo 11": Correct, "Apollo 12": Wrong} {
+		if got := MatchText(text, []string{"1984", "Apollo 11"}, DefaultTolerance); got != verdict {
+			t.Errorf("MatchText(%q) = %s; want %s", text, got, verdict)
+		}
+	}
+}
+
+func TestPartialCreditForSelectionsAndOrder(t *testing.T) {
+	selections := []struct {
+		picked []int
+		credit float64
+	}{
+		{[]int{0, 2, 4}, 1},
+		{[]int{4, 0}, 2.0 / 3},
+		{[]int{0, 1}, 0}, // One right, one wrong.
+		{[]int{1, 3}, 0}, // Never below zero.
+		{[]int{0, 2, 3}, 1.0 / 3},
+	}
+	for _, tc := range selections {
+		if got := SelectionCredit([]int{0, 2, 4}, tc.picked); math.Abs(got-tc.credit) > 1e-9 {
+			t.Errorf("SelectionCredit(%v) = %v; want %v", tc.picked, got, tc.credit)
+		}
+	}
+
+	orders := []struct {
+		order  []int
+		credit float64
+	}{
+		{[]int{0, 1, 2, 3}, 1},
+		{[]int{1, 0, 2, 3}, 5.0 / 6}, // One pair swapped.
+		{[]int{3, 2, 1, 0}, 0},
+	}
+	for _, tc := range orders {
+		if got := OrderCredit([]int{0, 1, 2, 3}, tc.order); math.Abs(got-tc.credit) > 1e-9 {
+			t.Errorf("OrderCredit(%v) = %v; want %v", tc.order, got, tc.credit)
+		}
+	}
+
+	ordering := models.Question{Type: models.QuestionOrdering, Options: []string{"a", "b", "c"}, CorrectOrder: []int{2, 0, 1}}
+	if _, err := Check(ordering, models.AnswerSubmission{Selections: []int{2, 0, 0}}, DefaultTolerance); err != ErrIncompleteOrder {
+		t.Errorf("Expected a repeated option to be rejected; got %v", err)
+	}
+	grade, err := Check(ordering, models.AnswerSubmission{Selections: []int{2, 1, 0}}, DefaultTolerance)
+	if err != nil || grade.Verdict != Partial || math.Abs(grade.Credit-2.0/3) > 1e-9 {
+		t.Errorf("Expected partial credit 2/3 for one misplaced option; got %+v, %v", grade, err)
+	}
+	multi := models.Question{Type: models.QuestionMultiSelect, Options: []string{"a", "b"}, CorrectIndexes: []int{0}}
+	if _, err := Check(multi, models.AnswerSubmission{Selections: []int{5}}, DefaultTolerance); err != ErrNotAnOption {
+		t.Errorf("Expected an out-of-range selection to be rejected; got %v", err)
+	}
+}
@@ -10,14 +10,69 @@ func RegisterHandlers(router *gin.Engine, gameServer *game.GameServer) {
 	// Setup a group for game-related routes
 	gameRoutes := router.Group("/game")
 	{
-		gameRoutes.POST("/start", gameServer.StartGameHandler)          // Start a new game session
-		gameRoutes.POST("/join/:sessionId", gameServer.JoinGameHandler) // Join an existing game session
-		gameRoutes.GET("/end/:sessionId", gameServer.EndGameHandler)    // End a game session
+		gameRoutes.POST("/start", gameServer.StartGameHandler)                // Start a new game session
+		gameRoutes.POST("/join/:sessionId", gameServer.JoinGameHandler)       // Join an existing game session
+		gameRoutes.POST("/resume/:sessionId", gameServer.ResumeGameHandler)   // Resume a session with a resume token
+		gameRoutes.POST("/team/:sessionId", gameServer.SwitchTeamHandler)     // Switch teams in a team-mode session
+		gameRoutes.POST("/control/:sessionId", gameServer.HostControlHandler) // Host pauses, resumes or skips a question
+		gameRoutes.POST("/review/:sessionId", gameServer.ReviewAnswerHandler) // Host accepts or rejects a borderline typed answer
+		gameRoutes.GET("/end/:sessionId", gameServer.EndGameHandler)          // End a game session
+	}
+
+	// Daily challenge: one shared question set and one attempt per player per day
+	dailyRoutes := router.Group("/daily")
+	{
+		dailyRoutes.POST("/start", gameServer.DailyStartHandler)            // Start today's attempt
+		dailyRoutes.GET("/leaderboard", gameServer.DailyLeaderboardHandler) // Rank a day's attempts
+	}
+
+	// Questions each returning player has answered, by the user ID the server issued them
+	playerRoutes := router.Group("/players")
+	{
+		playerRoutes.POST("", gameServer.IssueUserHandler)                                 // Issue a user ID and token
+		playerRoutes.GET("/:userId/answered", gameServer.AnsweredQuestionsHandler)         // Questions new games avoid; needs the user token
+		playerRoutes.DELETE("/:userId/answered", gameServer.ResetAnsweredQuestionsHandler) // Forget them; needs the user token
+	}
+
+	// Asynchronous head-to-head challenges
+	challengeRoutes := router.Group("/challenge")
+	{
+		challengeRoutes.POST("/create/:sessionId", gameServer.CreateChallengeHandler)   // Challenge someone to a finished game
+		challengeRoutes.GET("/:challengeId", gameServer.GetChallengeHandler)            // Challenge status and results
+		challengeRoutes.POST("/:challengeId/accept", gameServer.AcceptChallengeHandler) // Start the recipient's game
+	}
+
+	// Tournaments: registration, seeding and bracket state; matches are ordinary game sessions
+	tournamentRoutes := router.Group("/tournament")
+	{
+		tournamentRoutes.POST("", gameServer.RequireAdmin, gameServer.CreateTournamentHandler)                    // Open a tournament for registration (admin)
+		tournamentRoutes.GET("/:tournamentId", gameServer.GetTournamentHandler)                                   // Bracket or Swiss state as JSON
+		tournamentRoutes.POST("/:tournamentId/register", gameServer.RegisterTournamentHandler)                    // Register a player
+		tournamentRoutes.POST("/:tournamentId/start", gameServer.RequireAdmin, gameServer.StartTournamentHandler) // Seed and spawn the first round (admin)
+	}
+
+	// Admin API, authenticated with the admin token
+	adminRoutes := router.Group("/admin", gameServer.RequireAdmin)
+	{
+		adminRoutes.POST("/import", gameServer.ImportQuestionsHandler)                       // Import a question file into the local bank
+		adminRoutes.GET("/questions", gameServer.SearchQuestionsHandler)                     // Search the bank
+		adminRoutes.POST("/questions", gameServer.CreateQuestionHandler)                     // Add a question
+		adminRoutes.GET("/questions/:questionId", gameServer.GetQuestionHandler)             // One question
+		adminRoutes.PUT("/questions/:questionId", gameServer.UpdateQuestionHandler)          // Fix a question
+		adminRoutes.DELETE("/questions/:questionId", gameServer.DeleteQuestionHandler)       // Retire a question
+		adminRoutes.POST("/questions/:questionId/tags", gameServer.TagQuestionHandler)       // Add or remove tags
+		adminRoutes.GET("/questions/:questionId/history", gameServer.QuestionHistoryHandler) // Every revision of a question
+		adminRoutes.GET("/duplicates", gameServer.DuplicatesHandler)                         // Suspected duplicate questions
+		adminRoutes.POST("/duplicates/merge", gameServer.MergeDuplicatesHandler)             // Merge a pair of duplicates
 	}
 
 	// Questions and answers handling
-	router.GET("/questions/:sessionId", gameServer.QuestionsHandler) // Retrieve questions for the game
-	router.POST("/answer", gameServer.AnswerHandler)                 // Submit an answer
+	router.GET("/questions/:sessionId", gameServer.QuestionsHandler)            // Retrieve questions for the game
+	router.GET("/questions/:sessionId/:questionId", gameServer.QuestionHandler) // One question as shown, with its media
+	router.POST("/answer", gameServer.AnswerHandler)                            // Submit an answer
+	router.POST("/buzz", gameServer.BuzzHandler)                                // Buzz in on a question in buzzer mode
+	router.POST("/lifeline", gameServer.LifelineHandler)                        // Spend a lifeline in a single-player game
+	router.GET("/media/:token", gameServer.MediaHandler)                        // Fetch question media through a URL sent with its question
 
 	// Player status updates
 	router.POST("/player/finished", gameServer.MarkPlayerFinishedHandler) // Mark a player as finished
@@ -30,6 +85,9 @@ func RegisterHandlers(router *gin.Engine, gameServer *game.GameServer) {
 	// WebSocket endpoint for real-time interactions
 	router.GET("/ws", gameServer.WebSocketEndpoint)
 
+	// Server-Sent Events fallback for clients that cannot use WebSockets
+	router.GET("/events/:sessionId", gameServer.EventStreamHandler)
+
 	// Apply middleware for error handling (hypothetical example)
 	router.Use(ErrorHandlingMiddleware())
 }
@@ -0,0 +1,285 @@
+// Package history remembers which questions each returning player has answered, so new games
+// can leave them out. Players are known by the user ID the server issued them, and questions
+// by their dedupe.Key, which survives refetching. The history is kept in a log file that is
+// compacted as it grows.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is a question a player answered.
+type Entry struct {
+	Key string    `json:"key"` // The question's dedupe.Key.
+	At  time.Time `json:"at"`
+}
+
+// event is one line of the log file: an answered question, or a reset of a player's history.
+type event struct {
+	UserID string    `json:"userId"`
+	Key    string    `json:"key,omitempty"`
+	At     time.Time `json:"at"`
+	Reset  bool      `json:"reset,omitempty"`
+}
+
+// queued is an answer waiting in the queue, or a request to be told once those before it are written.
+type queued struct {
+	userID, key string
+	flushed     chan struct{}
+}
+
+// History is the answered questions of every player, saved to a log file.
+type History struct {
+	sync.Mutex
+	Path      string        // Log file the history is saved to.
+	MaxLength int           // Most recent questions remembered per player; 0 for no limit.
+	MaxAge    time.Duration // Questions answered longer ago are forgotten; 0 for no limit.
+	players   map[string][]Entry
+	logged    int         // Lines in the log file, to tell when compacting is worthwhile.
+	queue     chan queued // Answers for the writer to record; see Queue.
+}
+
+// queueSize is how many answers may wait for the writer before Queue drops them.
+const queueSize = 1024
+
+// Open loads the history saved at path, applying the retention limits, and compacts the log
+// file to what is kept. A missing file opens an empty history, and is created on the first
+// answer recorded.
+func Open(path string, maxLength int, maxAge time.Duration) (*History, error) {
+	h := &History{Path: path, MaxLength: maxLength, MaxAge: maxAge, players: make(map[string][]Entry), queue: make(chan queued, queueSize)}
+	if err := h.replay(); err != nil {
+		return nil, err
+	}
+	if h.logged > 0 {
+		if err := h.compact(); err != nil {
+			return nil, err
+		}
+	}
+	go h.write()
+	return h, nil
+}
+
+// Queue records that a player answered a question in the background, for callers that must
+// not wait on the log file, such as those holding a session's lock. Answers are recorded in
+// the order they are queued; failures are logged. Queue never blocks: if the log file falls
+// so far behind that the queue is full, the answer is dropped and logged.
+func (h *History) Queue(userID, key string) {
+	if userID == "" || key == "" {
+		return
+	}
+	select {
+	case h.queue <- queued{userID: userID, key: key}:
+	default:
+		log.Printf("Question history queue is full; not recording question %s for user %s", key, userID)
+	}
+}
+
+// Flush waits until every answer queued so far is recorded.
+func (h *History) Flush() {
+	flushed := make(chan struct{})
+	h.queue <- queued{flushed: flushed}
+	<-flushed
+}
+
+// write records queued answers for as long as the history is in use.
+func (h *History) write() {
+	for q := range h.queue {
+		if q.flushed != nil {
+			close(q.flushed)
+			continue
+		}
+		if err := h.Record(q.userID, q.key); err != nil {
+			log.Printf("Failed to record question history for user %s: %v", q.userID, err)
+		}
+	}
+}
+
+// Record notes that a player answered a question. A question answered again moves to the
+// newest end of the history.
+func (h *History) Record(userID, key string) error {
+	if userID == "" || key == "" {
+		return nil
+	}
+	h.Lock()
+	defer h.Unlock()
+
+	e := event{UserID: userID, Key: key, At: time.Now().UTC()}
+	h.apply(e)
+	return h.append(e)
+}
+
+// Seen returns the keys of the questions any of the given players has answered within the
+// retention limits.
+func (h *History) Seen(userIDs ...string) map[string]bool {
+	h.Lock()
+	defer h.Unlock()
+
+	seen := make(map[string]bool)
+	for _, userID := range userIDs {
+		for _, entry := range h.retained(userID) {
+			seen[entry.Key] = true
+		}
+	}
+	return seen
+}
+
+// Entries returns the questions a player has answered within the retention limits, oldest first.
+func (h *History) Entries(userID string) []Entry {
+	h.Lock()
+	defer h.Unlock()
+
+	return append([]Entry{}, h.retained(userID)...)
+}
+
+// Reset forgets every question a player has answered.
+func (h *History) Reset(userID string) error {
+	h.Lock()
+	defer h.Unlock()
+
+	if _, exists := h.players[userID]; !exists {
+		return nil
+	}
+	e := event{UserID: userID, At: time.Now().UTC(), Reset: true}
+	h.apply(e)
+	return h.append(e)
+}
+
+// apply adds an event to the history in memory, trimming the player's history to MaxLength.
+// The caller must hold the lock.
+func (h *History) apply(e event) {
+	if e.Reset {
+		delete(h.players, e.UserID)
+		return
+	}
+	entries := h.players[e.UserID]
+	for i, entry := range entries {
+		if entry.Key == e.Key {
+			entries = append(entries[:i:i], entries[i+1:]...)
+			break
+		}
+	}
+	entries = append(entries, Entry{Key: e.Key, At: e.At})
+	if h.MaxLength > 0 && len(entries) > h.MaxLength {
+		entries = append([]Entry{}, entries[len(entries)-h.MaxLength:]...)
+	}
+	h.players[e.UserID] = entries
+}
+
+// retained returns a player's entries not older than MaxAge. The caller must hold the lock.
+func (h *History) retained(userID string) []Entry {
+	entries := h.players[userID]
+	if h.MaxAge <= 0 {
+		return entries
+	}
+	cutoff := time.Now().Add(-h.MaxAge)
+	for i, entry := range entries {
+		if entry.At.After(cutoff) {
+			return entries[i:]
+		}
+	}
+	return nil
+}
+
+// append writes an event to the log file, compacting it once it holds over twice the lines
+// needed. The caller must hold the lock.
+func (h *History) append(e event) error {
+	if h.logged > 2*h.size()+1000 {
+		return h.compact()
+	}
+	file, err := os.OpenFile(h.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := json.NewEncoder(file).Encode(e); err != nil {
+		return err
+	}
+	h.logged++
+	return nil
+}
+
+// size returns how many entries the history holds. The caller must hold the lock.
+func (h *History) size() int {
+	size := 0
+	for _, entries := range h.players {
+		size += len(entries)
+	}
+	return size
+}
+
+// replay reads the log file into memory. The caller must hold the lock or own the history.
+func (h *History) replay() error {
+	file, err := os.Open(h.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		var e event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fmt.Errorf("%s:%d: %w", h.Path, line, err)
+		}
+		h.apply(e)
+		h.logged++
+	}
+	return scanner.Err()
+}
+
+// compact rewrites the log file with only the entries still retained, replacing the old file
+// once the new one is complete. The caller must hold the lock or own the history.
+func (h *History) compact() error {
+	var events []event
+	for userID := range h.players {
+		entries := h.retained(userID)
+		if len(entries) == 0 {
+			delete(h.players, userID)
+			continue
+		}
+		h.players[userID] = entries
+		for _, entry := range entries {
+			events = append(events, event{UserID: userID, Key: entry.Key, At: entry.At})
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.Path), 0o755); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(h.Path), ".history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	writer := bufio.NewWriter(temp)
+	encoder := json.NewEncoder(writer)
+	for _, e := range events {
+		if err := encoder.Encode(e); err != nil {
+			temp.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(temp.Name(), h.Path); err != nil {
+		return err
+	}
+	h.logged = len(events)
+	return nil
+}
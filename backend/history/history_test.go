@@ -0,0 +1,60 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistoryKeepsRecentQuestionsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answered.jsonl")
+	answered, err := Open(path, 2, 0)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	for _, key := range []string{"a", "b", "a", "c"} {
+		if err := answered.Record("ann", key); err != nil {
+			t.Fatalf("Failed to record %s: %v", key, err)
+		}
+	}
+	answered.Queue("bob", "d")
+	answered.Queue("cat", "e")
+	answered.Flush()
+	if err := answered.Reset("cat"); err != nil {
+		t.Fatalf("Failed to reset history: %v", err)
+	}
+
+	reopened, err := Open(path, 2, 0)
+	if err != nil {
+		t.Fatalf("Failed to reopen history: %v", err)
+	}
+	if entries := reopened.Entries("ann"); len(entries) != 2 || entries[0].Key != "a" || entries[1].Key != "c" {
+		t.Errorf("Expected ann's two latest questions, a then c; got %+v", entries)
+	}
+	if seen := reopened.Seen("ann", "bob", "cat"); len(seen) != 3 || !seen["d"] || seen["e"] {
+		t.Errorf("Expected a, c and d to be seen, and cat's reset; got %v", seen)
+	}
+
+	expiring, err := Open(path, 0, time.Nanosecond)
+	if err != nil {
+		t.Fatalf("Failed to reopen history: %v", err)
+	}
+	if seen := expiring.Seen("ann", "bob"); len(seen) != 0 {
+		t.Errorf("Expected questions older than the maximum age to be forgotten; got %v", seen)
+	}
+}
+
+func TestQueueDropsAnswersRatherThanBlock(t *testing.T) {
+	full := &History{queue: make(chan queued)} // No writer, so the queue is always full.
+
+	queued := make(chan struct{})
+	go func() {
+		full.Queue("ann", "question")
+		close(queued)
+	}()
+	select {
+	case <-queued:
+	case <-time.After(time.Second):
+		t.Fatal("Queue blocked on a full queue")
+	}
+}
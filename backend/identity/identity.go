@@ -0,0 +1,108 @@
+// Package identity issues players their user IDs. Each ID comes with a token the server
+// signed, so a request can only act for a user ID the server handed to the client making it,
+// however public the ID itself is. Each client is issued only a few IDs at a time, so
+// per-user limits such as one daily attempt cannot be dodged by minting new users.
+package identity
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid user token")
+	ErrTooManyUsers = errors.New("too many user IDs issued to this client; try again later")
+)
+
+// Issuer issues and checks user tokens. Replicas must share the secret.
+type Issuer struct {
+	sync.Mutex
+	Secret      []byte                 // Key signing tokens.
+	IssueLimit  int                    // User IDs one client may be issued per IssueWindow; 0 for no limit.
+	IssueWindow time.Duration          // Sliding window for IssueLimit.
+	issued      map[string][]time.Time // Recent issue times per client.
+}
+
+// NewIssuer signs tokens with secret. Without a secret, one is generated, and tokens issued
+// by this process stop working when it restarts.
+func NewIssuer(secret []byte) (*Issuer, error) {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	return &Issuer{Secret: secret}, nil
+}
+
+// Issue creates a new user ID and the token proving it.
+func (i *Issuer) Issue() (userID, token string) {
+	userID = uuid.New().String()
+	return userID, userID + "." + i.signature(userID)
+}
+
+// IssueTo issues a new user ID to a client, known by its address, unless the client has
+// reached its IssueLimit.
+func (i *Issuer) IssueTo(client string) (userID, token string, err error) {
+	if err := i.admit(client); err != nil {
+		return "", "", err
+	}
+	userID, token = i.Issue()
+	return userID, token, nil
+}
+
+// admit checks a client's issue limit, recording the issue if allowed.
+func (i *Issuer) admit(client string) error {
+	if i.IssueLimit <= 0 {
+		return nil
+	}
+	i.Lock()
+	defer i.Unlock()
+
+	now := time.Now()
+	if i.issued == nil {
+		i.issued = make(map[string][]time.Time)
+	}
+	// Forget clients whose issues have all left the window, so the map stays small.
+	for address, times := range i.issued {
+		if now.Sub(times[len(times)-1]) >= i.IssueWindow {
+			delete(i.issued, address)
+		}
+	}
+	recent := i.issued[client][:0]
+	for _, at := range i.issued[client] {
+		if now.Sub(at) < i.IssueWindow {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= i.IssueLimit {
+		i.issued[client] = recent
+		return ErrTooManyUsers
+	}
+	i.issued[client] = append(recent, now)
+	return nil
+}
+
+// Verify returns the user ID a token proves.
+func (i *Issuer) Verify(token string) (string, error) {
+	userID, signature, found := strings.Cut(token, ".")
+	if !found || userID == "" || !hmac.Equal([]byte(signature), []byte(i.signature(userID))) {
+		return "", ErrInvalidToken
+	}
+	return userID, nil
+}
+
+// signature signs a user ID.
+func (i *Issuer) signature(userID string) string {
+	mac := hmac.New(sha256.New, i.Secret)
+	mac.Write([]byte(userID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package identity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokensProveOnlyTheIssuedUserID(t *testing.T) {
+	issuer, err := NewIssuer([]byte("secret"))
+	if err != nil {
+		t.Fatalf("NewIssuer failed: %v", err)
+	}
+
+	userID, token := issuer.Issue()
+	if verified, err := issuer.Verify(token); err != nil || verified != userID {
+		t.Fatalf("Expected token to prove %s; got %q, %v", userID, verified, err)
+	}
+
+	otherID, _ := issuer.Issue()
+	_, signature, _ := strings.Cut(token, ".")
+	for _, forged := range []string{otherID + "." + signature, userID, userID + ".", ""} {
+		if _, err := issuer.Verify(forged); err != ErrInvalidToken {
+			t.Errorf("Expected %q to be refused; got %v", forged, err)
+		}
+	}
+
+	other, _ := NewIssuer([]byte("another secret"))
+	if _, err := other.Verify(token); err != ErrInvalidToken {
+		t.Errorf("A token should not verify under another secret; got %v", err)
+	}
+}
+
+func TestEachClientIsIssuedOnlyAFewUserIDs(t *testing.T) {
+	issuer, _ := NewIssuer([]byte("secret"))
+	issuer.IssueLimit = 2
+	issuer.IssueWindow = time.Hour
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := issuer.IssueTo("10.0.0.1"); err != nil {
+			t.Fatalf("Issue %d should be allowed; got %v", i+1, err)
+		}
+	}
+	if _, _, err := issuer.IssueTo("10.0.0.1"); err != ErrTooManyUsers {
+		t.Errorf("Expected the third issue to be refused; got %v", err)
+	}
+	if _, _, err := issuer.IssueTo("10.0.0.2"); err != nil {
+		t.Errorf("Another client should still be issued an ID; got %v", err)
+	}
+
+	issuer.IssueWindow = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if _, _, err := issuer.IssueTo("10.0.0.1"); err != nil {
+		t.Errorf("Expected the limit to reset once the window passed; got %v", err)
+	}
+}
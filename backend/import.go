@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gclluch/TriviaApp-ReactGo/bank"
+	"github.com/gclluch/TriviaApp-ReactGo/importer"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/spf13/viper"
+)
+
+// runImport adds the questions in the named files to the local question bank, as in
+//
+//	main import [-format csv|yaml|opentdb|gift|moodlexml] [-bank file] [-dry-run] file...
+//
+// Every file is checked before any is imported, and every problem is printed with its file
+// and line. Questions repeating one in the bank or already read word for word are skipped;
+// reworded repeats are imported but listed for review. It returns the process exit status.
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "format of the files; guessed from each file's extension if empty")
+	bankFile := flags.String("bank", viper.GetString("QUESTIONS_FILE"), "question bank to import into")
+	dryRun := flags.Bool("dry-run", false, "check the files without importing them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: import [-format format] [-bank file] [-dry-run] file...")
+		return 2
+	}
+
+	questionBank, err := bank.Open(*bankFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open question bank: %v\n", err)
+		return 1
+	}
+	known := questionBank.Questions()
+
+	var questions []models.Question
+	failed := false
+	for _, filename := range flags.Args() {
+		imported, duplicates, err := importFile(filename, importer.Format(*format), append(known, questions...))
+		var problems importer.Errors
+		switch {
+		case errors.As(err, &problems):
+			// Problems are printed as filename:line: message, as compilers report errors.
+			for _, problem := range problems {
+				if problem.Line == 0 {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", filename, problem.Message)
+				} else {
+					fmt.Fprintf(os.Stderr, "%s:%d: %s\n", filename, problem.Line, problem.Message)
+				}
+			}
+			failed = true
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filename, err)
+			failed = true
+		default:
+			for _, duplicate := range duplicates {
+				fmt.Printf("%s:%d: %s\n", filename, duplicate.Line, describeDuplicate(duplicate))
+			}
+			fmt.Printf("%s: %d questions\n", filename, len(imported))
+			questions = append(questions, imported...)
+		}
+	}
+	if failed {
+		fmt.Fprintln(os.Stderr, "Nothing was imported.")
+		return 1
+	}
+	if *dryRun {
+		fmt.Printf("%d questions are ready to import.\n", len(questions))
+		return 0
+	}
+
+	added, err := questionBank.Add(questions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to import questions: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Imported %d questions into %s.\n", len(added), *bankFile)
+	return 0
+}
+
+// importFile reads the questions in one file, guessing its format from its name if none is
+// given, and leaving out those repeating one of existing.
+func importFile(filename string, format importer.Format, existing []models.Question) ([]models.Question, []importer.Duplicate, error) {
+	if format == "" {
+		var known bool
+		if format, known = importer.FormatOf(filename); !known {
+			return nil, nil, fmt.Errorf("cannot tell the format from the name; use -format")
+		}
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	return importer.Import(file, format, existing)
+}
+
+// describeDuplicate says what became of a duplicate and which question it repeats.
+func describeDuplicate(duplicate importer.Duplicate) string {
+	what := "skipped; same question as"
+	if !duplicate.Exact {
+		what = "imported, but worded much like"
+	}
+	switch {
+	case duplicate.Of != "":
+		return fmt.Sprintf("%s bank question %s", what, duplicate.Of)
+	case duplicate.OfLine > 0:
+		return fmt.Sprintf("%s line %d", what, duplicate.OfLine)
+	}
+	return fmt.Sprintf("%s a question in an earlier file", what)
+}
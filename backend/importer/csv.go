@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// csvColumns are the columns a CSV file may have, in any order. Only question is required.
+// Options, answers and tags hold lists separated by "|":
+//
+//	type,question,options,answer,unit
+//	multiple,What is the capital of France?,Paris|Lyon|Nice,Paris,
+//	multiselect,Which are prime?,2|4|5|9,2|5,
+//	text,Who painted the Mona Lisa?,,Leonardo da Vinci|Da Vinci,
+//	numeric,How tall is the Eiffel Tower?,,330,metres
+//	ordering,Oldest first,Rome|Paris|New York,,
+var csvColumns = map[string]bool{
+	"type": true, "question": true, "options": true, "answer": true, "unit": true,
+	"category": true, "difficulty": true, "tags": true,
+}
+
+// readCSV reads questions from CSV with a header row naming its columns.
+func readCSV(r io.Reader) ([]parsedQuestion, Errors) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Row lengths are checked against the header below, with line numbers.
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, Errors{{Line: 1, Message: fmt.Sprintf("failed to read header: %v", err)}}
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !csvColumns[name] {
+			return nil, Errors{{Line: 1, Message: fmt.Sprintf("unknown column %q", name)}}
+		}
+		columns[name] = i
+	}
+	if _, exists := columns["question"]; !exists {
+		return nil, Errors{{Line: 1, Message: "missing column \"question\""}}
+	}
+
+	var parsed []parsedQuestion
+	var errs Errors
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return parsed, append(errs, LineError{Message: err.Error()})
+			}
+			// The reader moves past a malformed row, so carry on to report the rest.
+			errs = append(errs, LineError{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if len(row) != len(header) {
+			errs = append(errs, LineError{Line: line, Message: fmt.Sprintf("row has %d fields; the header has %d", len(row), len(header))})
+			continue
+		}
+
+		cell := func(column string) string {
+			if i, exists := columns[column]; exists {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		question, err := record{
+			Type:       cell("type"),
+			Question:   cell("question"),
+			Options:    splitList(cell("options")),
+			Answers:    splitList(cell("answer")),
+			Unit:       cell("unit"),
+			Category:   cell("category"),
+			Difficulty: cell("difficulty"),
+			Tags:       splitList(cell("tags")),
+		}.toQuestion()
+		if err != nil {
+			errs = append(errs, LineError{Line: line, Message: err.Error()})
+			continue
+		}
+		parsed = append(parsed, parsedQuestion{line: line, question: question})
+	}
+	return parsed, errs
+}
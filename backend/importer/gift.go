@@ -0,0 +1,302 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// giftBlock is one question of a GIFT file, with the line it starts on.
+type giftBlock struct {
+	line int
+	text string
+}
+
+// readGIFT reads questions in Moodle's GIFT format. Multiple choice, multiple answer (with
+// answer weights), true/false, short answer and numerical questions are read; matching,
+// essay and description items are reported as unsupported.
+func readGIFT(r io.Reader) ([]parsedQuestion, Errors) {
+	blocks, err := giftBlocks(r)
+	if err != nil {
+		return nil, Errors{{Message: err.Error()}}
+	}
+
+	var parsed []parsedQuestion
+	var errs Errors
+	for _, block := range blocks {
+		question, err := parseGIFTQuestion(block.text)
+		if err != nil {
+			errs = append(errs, LineError{Line: block.line, Message: err.Error()})
+			continue
+		}
+		parsed = append(parsed, parsedQuestion{line: block.line, question: question})
+	}
+	return parsed, errs
+}
+
+// giftBlocks splits a GIFT file into questions, which are separated by blank lines.
+// Comments and category commands are dropped.
+func giftBlocks(r io.Reader) ([]giftBlock, error) {
+	var blocks []giftBlock
+	var current *giftBlock
+	depth := 0 // Answer blocks may contain blank lines.
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case strings.HasPrefix(trimmed, "//"), strings.HasPrefix(trimmed, "$CATEGORY:"):
+			continue
+		case trimmed == "" && depth == 0:
+			current = nil
+			continue
+		}
+		if current == nil {
+			blocks = append(blocks, giftBlock{line: line})
+			current = &blocks[len(blocks)-1]
+		} else {
+			current.text += "\n"
+		}
+		current.text += text
+		depth += strings.Count(text, "{") - strings.Count(text, `\{`) - strings.Count(text, "}") + strings.Count(text, `\}`)
+	}
+	return blocks, scanner.Err()
+}
+
+// parseGIFTQuestion reads one GIFT question.
+func parseGIFTQuestion(block string) (models.Question, error) {
+	block = strings.TrimSpace(block)
+	if strings.HasPrefix(block, "::") {
+		end := indexUnescaped(block, "::", 2)
+		if end < 0 {
+			return models.Question{}, fmt.Errorf("question title is not closed with ::")
+		}
+		block = strings.TrimSpace(block[end+2:])
+	}
+	for _, marker := range []string{"[html]", "[plain]", "[moodle]", "[markdown]"} {
+		block = strings.TrimPrefix(block, marker)
+	}
+
+	open := indexUnescaped(block, "{", 0)
+	if open < 0 {
+		return models.Question{}, fmt.Errorf("descriptions without an answer block are not supported")
+	}
+	closing := indexUnescaped(block, "}", open+1)
+	if closing < 0 {
+		return models.Question{}, fmt.Errorf("answer block is not closed with }")
+	}
+	text := giftUnescape(strings.TrimSpace(block[:open]))
+	if after := strings.TrimSpace(block[closing+1:]); after != "" {
+		// An answer block in mid-sentence marks a blank to fill in.
+		text += " _____ " + giftUnescape(after)
+	}
+	question := models.Question{QuestionText: strings.TrimSpace(text)}
+
+	answerBlock := strings.TrimSpace(block[open+1 : closing])
+	switch {
+	case answerBlock == "":
+		return question, fmt.Errorf("essay questions are not supported")
+	case strings.HasPrefix(answerBlock, "#"):
+		return giftNumeric(question, answerBlock[1:])
+	}
+	switch strings.ToUpper(strings.TrimSpace(withoutFeedback(answerBlock))) {
+	case "T", "TRUE":
+		question.Type = models.QuestionBoolean
+		question.CorrectIndex = 0
+		return question, nil
+	case "F", "FALSE":
+		question.Type = models.QuestionBoolean
+		question.CorrectIndex = 1
+		return question, nil
+	}
+	return giftChoices(question, answerBlock)
+}
+
+// giftAnswer is one answer in a GIFT answer block.
+type giftAnswer struct {
+	correct bool     // Marked with = rather than ~.
+	weight  *float64 // Percentage given as ~%50%, if any.
+	text    string
+}
+
+// giftChoices reads an answer block of = and ~ answers: one = among ~ answers is multiple
+// choice, weighted ~ answers are multiple answer, and only = answers are short answer.
+func giftChoices(question models.Question, answerBlock string) (models.Question, error) {
+	answers, err := giftAnswers(answerBlock)
+	if err != nil {
+		return question, err
+	}
+
+	var correct, wrong, weighted int
+	for _, answer := range answers {
+		switch {
+		case answer.weight != nil:
+			weighted++
+		case answer.correct:
+			correct++
+		default:
+			wrong++
+		}
+	}
+
+	switch {
+	case weighted > 0:
+		question.Type = models.QuestionMultiSelect
+		for i, answer := range answers {
+			question.Options = append(question.Options, answer.text)
+			if answer.correct || (answer.weight != nil && *answer.weight > 0) {
+				question.CorrectIndexes = append(question.CorrectIndexes, i)
+			}
+		}
+	case wrong == 0:
+		question.Type = models.QuestionFreeText
+		for _, answer := range answers {
+			question.Answers = append(question.Answers, answer.text)
+		}
+	case correct == 1:
+		question.Type = models.QuestionMultiple
+		for i, answer := range answers {
+			question.Options = append(question.Options, answer.text)
+			if answer.correct {
+				question.CorrectIndex = i
+			}
+		}
+	default:
+		return question, fmt.Errorf("multiple choice questions need exactly one = answer, or weights on every correct ~ answer")
+	}
+	return question, nil
+}
+
+// giftAnswers splits an answer block into its answers, dropping their feedback.
+func giftAnswers(answerBlock string) ([]giftAnswer, error) {
+	var answers []giftAnswer
+	var text strings.Builder
+	inFeedback := false
+	finish := func() error {
+		if len(answers) == 0 {
+			if strings.TrimSpace(text.String()) != "" {
+				return fmt.Errorf("answers must start with = or ~")
+			}
+			return nil
+		}
+		answer := &answers[len(answers)-1]
+		answer.text = strings.TrimSpace(text.String())
+		if strings.HasPrefix(answer.text, "%") {
+			end := strings.Index(answer.text[1:], "%")
+			if end < 0 {
+				return fmt.Errorf("answer weight %q is not closed with %%", answer.text)
+			}
+			weight, err := strconv.ParseFloat(answer.text[1:end+1], 64)
+			if err != nil {
+				return fmt.Errorf("answer weight %q is not a number", answer.text[1:end+1])
+			}
+			answer.weight = &weight
+			answer.text = strings.TrimSpace(answer.text[end+2:])
+		}
+		if answer.text == "" {
+			return fmt.Errorf("answers must not be empty")
+		}
+		return nil
+	}
+
+	runes := []rune(answerBlock)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			if !inFeedback {
+				text.WriteString(giftUnescape(string(runes[i-1 : i+1])))
+			}
+		case r == '=' || r == '~':
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			answers = append(answers, giftAnswer{correct: r == '='})
+			text.Reset()
+			inFeedback = false
+		case r == '#':
+			inFeedback = true
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '>' && !inFeedback:
+			return nil, fmt.Errorf("matching questions are not supported")
+		case !inFeedback:
+			text.WriteRune(r)
+		}
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("answer block has no answers")
+	}
+	return answers, nil
+}
+
+// giftNumeric reads a numerical answer block, without its leading #: a value, a value with
+// an error margin as 1989:2, or a range as 1980..2000. The margin is dropped, as numeric
+// questions score by closeness; a range stands for its midpoint.
+func giftNumeric(question models.Question, answerBlock string) (models.Question, error) {
+	answer := strings.TrimSpace(withoutFeedback(answerBlock))
+	if strings.HasPrefix(answer, "=") {
+		// Several weighted answers; the first is taken as the value.
+		answer = strings.TrimSpace(strings.SplitN(answer[1:], "=", 2)[0])
+		if strings.HasPrefix(answer, "%") {
+			if end := strings.Index(answer[1:], "%"); end >= 0 {
+				answer = strings.TrimSpace(answer[end+2:])
+			}
+		}
+	}
+
+	var value float64
+	var err error
+	if low, high, isRange := strings.Cut(answer, ".."); isRange {
+		var from, to float64
+		if from, err = strconv.ParseFloat(strings.TrimSpace(low), 64); err == nil {
+			to, err = strconv.ParseFloat(strings.TrimSpace(high), 64)
+		}
+		value = (from + to) / 2
+	} else {
+		exact, _, _ := strings.Cut(answer, ":")
+		value, err = strconv.ParseFloat(strings.TrimSpace(exact), 64)
+	}
+	if err != nil {
+		return question, fmt.Errorf("numerical answer %q is not a number", answer)
+	}
+	question.Type = models.QuestionNumeric
+	question.Value = &value
+	return question, nil
+}
+
+// withoutFeedback drops the feedback after the first unescaped # of an answer.
+func withoutFeedback(answer string) string {
+	if i := indexUnescaped(answer, "#", 0); i >= 0 {
+		return answer[:i]
+	}
+	return answer
+}
+
+// indexUnescaped returns the index of the first occurrence of sub at or after from that is
+// not escaped with a backslash, or -1.
+func indexUnescaped(s, sub string, from int) int {
+	for i := from; i+len(sub) <= len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case strings.HasPrefix(s[i:], sub):
+			return i
+		}
+	}
+	return -1
+}
+
+// giftEscapes are GIFT's escape sequences.
+var giftEscapes = strings.NewReplacer(`\~`, "~", `\=`, "=", `\#`, "#", `\{`, "{", `\}`, "}", `\:`, ":", `\n`, "\n", `\\`, `\`)
+
+// giftUnescape replaces GIFT's escape sequences with the characters they stand for.
+func giftUnescape(text string) string {
+	return giftEscapes.Replace(text)
+}
@@ -0,0 +1,256 @@
+// Package importer reads question banks written in other formats, so quizzes made elsewhere
+// can be brought into the local bank. Every problem found is reported with the line of the
+// question it concerns, as is every question left out for repeating one already known.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gclluch/TriviaApp-ReactGo/dedupe"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gclluch/TriviaApp-ReactGo/services"
+)
+
+// Format names a question file format.
+type Format string
+
+const (
+	CSV       Format = "csv"       // One question per row, under a header row; see readCSV.
+	YAML      Format = "yaml"      // A list of questions with the same fields as CSV.
+	OpenTDB   Format = "opentdb"   // An Open Trivia Database API response or its list of results.
+	GIFT      Format = "gift"      // Moodle's GIFT text format.
+	MoodleXML Format = "moodlexml" // Moodle's XML export format.
+)
+
+// ErrUnknownFormat is returned for a format the importer cannot read.
+var ErrUnknownFormat = errors.New("unknown import format")
+
+// FormatOf guesses a file's format from its extension.
+func FormatOf(filename string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return CSV, true
+	case ".yaml", ".yml":
+		return YAML, true
+	case ".json":
+		return OpenTDB, true
+	case ".gift", ".txt":
+		return GIFT, true
+	case ".xml":
+		return MoodleXML, true
+	}
+	return "", false
+}
+
+// LineError is a problem with the question starting on a line of an imported file. Line 0
+// means the problem is with the file as a whole.
+type LineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"error"`
+}
+
+func (e LineError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Errors is every problem found in an imported file.
+type Errors []LineError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, lineErr := range e {
+		messages[i] = lineErr.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Duplicate is a question that repeats one in the bank or earlier in the file. Exact repeats
+// are left out of the import; reworded ones with the same answer are kept, as they may yet be
+// different questions, and listed for review.
+type Duplicate struct {
+	Line     int    `json:"line"`
+	Question string `json:"question"`
+	Of       string `json:"duplicateOf,omitempty"`     // ID of the bank question it repeats.
+	OfLine   int    `json:"duplicateOfLine,omitempty"` // Line of the earlier question in the file it repeats.
+	dedupe.Match
+}
+
+// Import reads every question in a file of the given format. Questions are checked as the
+// bank would check them. If any question has a problem, Import returns no questions and an
+// Errors listing every problem, so the whole file can be fixed in one go. Questions repeating
+// one of existing, or one earlier in the file, are returned as duplicates.
+func Import(r io.Reader, format Format, existing []models.Question) ([]models.Question, []Duplicate, error) {
+	var parsed []parsedQuestion
+	var errs Errors
+	switch format {
+	case CSV:
+		parsed, errs = readCSV(r)
+	case YAML:
+		parsed, errs = readYAML(r)
+	case OpenTDB:
+		parsed, errs = readOpenTDB(r)
+	case GIFT:
+		parsed, errs = readGIFT(r)
+	case MoodleXML:
+		parsed, errs = readMoodleXML(r)
+	default:
+		return nil, nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+
+	for i := range parsed {
+		question := &parsed[i].question
+		if err := services.NormalizeQuestion(question); err != nil {
+			errs = append(errs, LineError{Line: parsed[i].line, Message: err.Error()})
+			continue
+		}
+		if question.Source == "" {
+			question.Source = string(format)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
+	if len(parsed) == 0 {
+		return nil, nil, Errors{{Message: "no questions found"}}
+	}
+	questions, duplicates := removeDuplicates(parsed, existing)
+	return questions, duplicates, nil
+}
+
+// removeDuplicates returns the parsed questions that are not exact repeats of one of existing
+// or of an earlier one, and every duplicate found.
+func removeDuplicates(parsed []parsedQuestion, existing []models.Question) ([]models.Question, []Duplicate) {
+	index := dedupe.NewIndex()
+	for _, question := range existing {
+		index.Add(question)
+	}
+	var kept []parsedQuestion
+	var duplicates []Duplicate
+	for _, entry := range parsed {
+		match, repeats := index.Find(entry.question)
+		if repeats {
+			duplicate := Duplicate{Line: entry.line, Question: entry.question.QuestionText, Match: match}
+			if match.Position < len(existing) {
+				duplicate.Of = existing[match.Position].ID
+			} else {
+				duplicate.OfLine = kept[match.Position-len(existing)].line
+			}
+			duplicates = append(duplicates, duplicate)
+			if match.Exact {
+				continue
+			}
+		}
+		index.Add(entry.question)
+		kept = append(kept, entry)
+	}
+
+	questions := make([]models.Question, len(kept))
+	for i, entry := range kept {
+		questions[i] = entry.question
+	}
+	return questions, duplicates
+}
+
+// parsedQuestion is a question read from a file, with the line it starts on.
+type parsedQuestion struct {
+	line     int
+	question models.Question
+}
+
+// record is a question as written in the formats with named fields, CSV and YAML. Answers
+// name the correct options, or are the accepted answers of free-text and numeric questions.
+type record struct {
+	Type       string
+	Question   string
+	Options    []string
+	Answers    []string
+	Unit       string
+	Category   string
+	Difficulty string
+	Tags       []string
+}
+
+// toQuestion builds the question a record describes.
+func (r record) toQuestion() (models.Question, error) {
+	question := models.Question{
+		Type:         strings.ToLower(strings.TrimSpace(r.Type)),
+		QuestionText: strings.TrimSpace(r.Question),
+		Options:      r.Options,
+		Unit:         r.Unit,
+		Category:     strings.TrimSpace(r.Category),
+		Difficulty:   r.Difficulty,
+		Tags:         r.Tags,
+	}
+	if question.QuestionText == "" {
+		return question, fmt.Errorf("question text is missing")
+	}
+
+	switch question.QuestionType() {
+	case models.QuestionMultiple, models.QuestionBoolean:
+		if question.QuestionType() == models.QuestionBoolean && len(question.Options) == 0 {
+			question.Options = append([]string{}, models.BooleanOptions...)
+		}
+		if len(r.Answers) != 1 {
+			return question, fmt.Errorf("give exactly one correct answer")
+		}
+		index, err := optionIndex(question.Options, r.Answers[0])
+		if err != nil {
+			return question, err
+		}
+		question.CorrectIndex = index
+	case models.QuestionMultiSelect:
+		for _, answer := range r.Answers {
+			index, err := optionIndex(question.Options, answer)
+			if err != nil {
+				return question, err
+			}
+			question.CorrectIndexes = append(question.CorrectIndexes, index)
+		}
+	case models.QuestionFreeText:
+		question.Answers = r.Answers
+	case models.QuestionNumeric:
+		if len(r.Answers) != 1 {
+			return question, fmt.Errorf("give the value as the only answer")
+		}
+		value, err := strconv.ParseFloat(r.Answers[0], 64)
+		if err != nil {
+			return question, fmt.Errorf("value %q is not a number", r.Answers[0])
+		}
+		question.Value = &value
+	case models.QuestionOrdering:
+		// Options are listed in the correct order.
+		if len(r.Answers) > 0 {
+			return question, fmt.Errorf("ordering questions list their options in order instead of giving answers")
+		}
+	}
+	return question, nil
+}
+
+// optionIndex finds the option an answer names, ignoring case and surrounding space.
+func optionIndex(options []string, answer string) (int, error) {
+	for i, option := range options {
+		if strings.EqualFold(strings.TrimSpace(option), strings.TrimSpace(answer)) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("answer %q is not one of the options", answer)
+}
+
+// splitList splits a list written in one CSV cell, with entries separated by "|".
+func splitList(cell string) []string {
+	var entries []string
+	for _, entry := range strings.Split(cell, "|") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestImportReadsEveryFormat(t *testing.T) {
+	files := map[Format]string{
+		CSV: `type,question,options,answer,unit
+,What is the capital of France?,Paris|Lyon|Nice,paris,
+multiselect,Which are prime?,2|4|5|9,2|5,
+numeric,How tall is the Eiffel Tower?,,330,metres
+`,
+		YAML: `- question: What is the capital of France?
+  options: [Paris, Lyon, Nice]
+  answer: Paris
+- type: text
+  question: Who painted the Mona Lisa?
+  answer: [Leonardo da Vinci, Da Vinci]
+- type: ordering
+  question: Oldest first
+  options: [Rome, Paris, New York]
+`,
+		OpenTDB: `{"response_code": 0, "results": [
+  {"type": "multiple", "question": "Who wrote &quot;Hamlet&quot;?", "correct_answer": "Shakespeare", "incorrect_answers": ["Marlowe", "Jonson"]},
+  {"type": "boolean", "question": "The sky is green.", "correct_answer": "False", "incorrect_answers": ["True"]},
+  {"type": "multiple", "question": "2 + 2?", "correct_answer": "4", "incorrect_answers": ["3", "5"]}
+]}`,
+		GIFT: `// A comment
+::Capital:: What is the capital of France? {=Paris ~Lyon ~Nice#Not quite}
+
+Which are prime? {
+  ~%50%2
+  ~%-100%4
+  ~%50%5
+}
+
+The sky is green. {F}
+
+In what year did the Berlin Wall fall? {#1989:1}
+`,
+		MoodleXML: `<?xml version="1.0"?>
+<quiz>
+  <question type="category"><category><text>$course$/Geography</text></category></question>
+  <question type="multichoice">
+    <questiontext format="html"><text><![CDATA[<p>What is the capital of <b>France</b>?</p>]]></text></questiontext>
+    <single>true</single>
+    <answer fraction="100"><text>Paris</text></answer>
+    <answer fraction="0"><text>Lyon</text></answer>
+  </question>
+  <question type="shortanswer">
+    <questiontext format="plain_text"><text>Who painted the Mona Lisa?</text></questiontext>
+    <answer fraction="100"><text>Leonardo da Vinci</text></answer>
+  </question>
+  <question type="truefalse">
+    <questiontext format="plain_text"><text>Water is wet.</text></questiontext>
+    <answer fraction="100"><text>true</text></answer>
+    <answer fraction="0"><text>false</text></answer>
+  </question>
+</quiz>`,
+	}
+
+	for format, file := range files {
+		questions, _, err := Import(strings.NewReader(file), format, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", format, err)
+			continue
+		}
+		count, correct := 3, "Paris"
+		switch format {
+		case GIFT:
+			count = 4
+		case OpenTDB:
+			correct = "Shakespeare"
+		}
+		if len(questions) != count {
+			t.Errorf("%s: expected %d questions; got %d", format, count, len(questions))
+			continue
+		}
+		if first := questions[0]; first.Type != models.QuestionMultiple || first.Options[first.CorrectIndex] != correct {
+			t.Errorf("%s: expected a multiple choice question answered %s; got %+v", format, correct, first)
+		}
+	}
+
+	gift, _, _ := Import(strings.NewReader(files[GIFT]), GIFT, nil)
+	if prime := gift[1]; prime.Type != models.QuestionMultiSelect || len(prime.CorrectIndexes) != 2 {
+		t.Errorf("Expected weighted GIFT answers to make a multi-select question; got %+v", prime)
+	}
+	if year := gift[3]; year.Type != models.QuestionNumeric || year.NumericValue() != 1989 {
+		t.Errorf("Expected a numerical GIFT question worth 1989; got %+v", year)
+	}
+	moodle, _, _ := Import(strings.NewReader(files[MoodleXML]), MoodleXML, nil)
+	if moodle[0].QuestionText != "What is the capital of France?" {
+		t.Errorf("Expected HTML to be stripped from Moodle text; got %q", moodle[0].QuestionText)
+	}
+}
+
+func TestImportReportsEveryProblemWithItsLine(t *testing.T) {
+	csv := `question,options,answer
+What is the capital of France?,Paris|Lyon,Paris
+What is the capital of Spain?,Madrid|Seville,Barcelona
+,Paris|Lyon,Paris
+Which is bigger?,One,One
+`
+	_, _, err := Import(strings.NewReader(csv), CSV, nil)
+	var problems Errors
+	if !errors.As(err, &problems) {
+		t.Fatalf("Expected import problems; got %v", err)
+	}
+	lines := make([]int, len(problems))
+	for i, problem := range problems {
+		lines[i] = problem.Line
+	}
+	if len(lines) != 3 || lines[0] != 3 || lines[1] != 4 || lines[2] != 5 {
+		t.Errorf("Expected problems on lines 3, 4 and 5; got %v", problems)
+	}
+
+	gift := "Matching {\n=cat -> meow\n=dog -> woof\n}\n\nEssay {}\n"
+	_, _, err = Import(strings.NewReader(gift), GIFT, nil)
+	if !errors.As(err, &problems) || len(problems) != 2 || problems[0].Line != 1 || problems[1].Line != 6 {
+		t.Errorf("Expected unsupported GIFT questions on lines 1 and 6; got %v", err)
+	}
+}
+
+func TestImportSkipsExactRepeatsAndListsRewordedOnes(t *testing.T) {
+	csv := `question,options,answer
+What is the capital of France?,Paris|Lyon,Paris
+What is the capital city of France?,Paris|Nice,Paris
+Which is the largest ocean?,Pacific|Atlantic,Pacific
+`
+	bank := []models.Question{{ID: "7", QuestionText: "Which is the LARGEST ocean", Options: []string{"Pacific", "Indian"}}}
+	questions, duplicates, err := Import(strings.NewReader(csv), CSV, bank)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(questions) != 2 {
+		t.Errorf("Expected the exact repeat of a bank question to be skipped; got %+v", questions)
+	}
+	if len(duplicates) != 2 || duplicates[0].Exact || duplicates[0].OfLine != 2 || !duplicates[1].Exact || duplicates[1].Of != "7" {
+		t.Errorf("Expected a reworded repeat of line 2 and an exact repeat of question 7; got %+v", duplicates)
+	}
+}
@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// moodleQuestion is a <question> element of a Moodle XML export.
+type moodleQuestion struct {
+	Type    string         `xml:"type,attr"`
+	Text    moodleText     `xml:"questiontext"`
+	Single  string         `xml:"single"` // "true" for one correct answer, "false" for several.
+	Answers []moodleAnswer `xml:"answer"`
+}
+
+// moodleText is text that may be HTML, as its format attribute says.
+type moodleText struct {
+	Format string `xml:"format,attr"`
+	Text   string `xml:"text"`
+}
+
+// moodleAnswer is an answer with the percentage of the marks it earns.
+type moodleAnswer struct {
+	Fraction string `xml:"fraction,attr"`
+	Format   string `xml:"format,attr"`
+	Text     string `xml:"text"`
+}
+
+// readMoodleXML reads questions from a Moodle XML export. Multiple choice (with one or several
+// correct answers), true/false, short answer, numerical and ordering questions are read;
+// categories are skipped and other types are reported as unsupported.
+func readMoodleXML(r io.Reader) ([]parsedQuestion, Errors) {
+	decoder := xml.NewDecoder(r)
+
+	var parsed []parsedQuestion
+	var errs Errors
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := decoder.InputPos()
+		if err != nil {
+			return parsed, append(errs, LineError{Line: line, Message: err.Error()})
+		}
+		start, isStart := token.(xml.StartElement)
+		if !isStart || start.Name.Local != "question" {
+			continue
+		}
+
+		var element moodleQuestion
+		if err := decoder.DecodeElement(&element, &start); err != nil {
+			return parsed, append(errs, LineError{Line: line, Message: err.Error()})
+		}
+		if element.Type == "category" {
+			continue
+		}
+		question, err := element.toQuestion()
+		if err != nil {
+			errs = append(errs, LineError{Line: line, Message: err.Error()})
+			continue
+		}
+		parsed = append(parsed, parsedQuestion{line: line, question: question})
+	}
+	return parsed, errs
+}
+
+// toQuestion builds the question a Moodle question element describes.
+func (q moodleQuestion) toQuestion() (models.Question, error) {
+	question := models.Question{QuestionText: moodlePlainText(q.Text.Text, q.Text.Format)}
+
+	switch q.Type {
+	case "multichoice":
+		question.Type = models.QuestionMultiple
+		if strings.TrimSpace(q.Single) == "false" {
+			question.Type = models.QuestionMultiSelect
+		}
+		for i, answer := range q.Answers {
+			question.Options = append(question.Options, moodlePlainText(answer.Text, answer.Format))
+			if fraction, err := answer.fraction(); err != nil {
+				return question, err
+			} else if fraction <= 0 {
+				continue
+			}
+			if question.Type == models.QuestionMultiSelect {
+				question.CorrectIndexes = append(question.CorrectIndexes, i)
+			} else {
+				question.CorrectIndex = i
+			}
+		}
+	case "truefalse":
+		question.Type = models.QuestionBoolean
+		question.CorrectIndex = -1
+		for _, answer := range q.Answers {
+			if fraction, err := answer.fraction(); err != nil {
+				return question, err
+			} else if fraction >= 100 {
+				question.CorrectIndex, _ = optionIndex(models.BooleanOptions, answer.Text)
+			}
+		}
+	case "shortanswer":
+		question.Type = models.QuestionFreeText
+		for _, answer := range q.Answers {
+			if fraction, err := answer.fraction(); err != nil {
+				return question, err
+			} else if fraction >= 100 {
+				question.Answers = append(question.Answers, moodlePlainText(answer.Text, answer.Format))
+			}
+		}
+	case "numerical":
+		question.Type = models.QuestionNumeric
+		for _, answer := range q.Answers {
+			if fraction, err := answer.fraction(); err != nil {
+				return question, err
+			} else if fraction < 100 {
+				continue
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(answer.Text), 64)
+			if err != nil {
+				return question, fmt.Errorf("numerical answer %q is not a number", answer.Text)
+			}
+			question.Value = &value
+			break
+		}
+	case "ordering":
+		// Answers are listed in the correct order.
+		question.Type = models.QuestionOrdering
+		for _, answer := range q.Answers {
+			question.Options = append(question.Options, moodlePlainText(answer.Text, answer.Format))
+		}
+	default:
+		return question, fmt.Errorf("%s questions are not supported", q.Type)
+	}
+	if question.QuestionText == "" {
+		return question, fmt.Errorf("question text is missing")
+	}
+	return question, nil
+}
+
+// fraction returns the percentage of the marks an answer earns.
+func (a moodleAnswer) fraction() (float64, error) {
+	fraction, err := strconv.ParseFloat(strings.TrimSpace(a.Fraction), 64)
+	if err != nil {
+		return 0, fmt.Errorf("answer fraction %q is not a number", a.Fraction)
+	}
+	return fraction, nil
+}
+
+var (
+	htmlBreak = regexp.MustCompile(`(?i)<(br|/p|/div|/li|/h[1-6])\b[^>]*>`) // Tags ending a line of text.
+	htmlTag   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// moodlePlainText turns Moodle text into plain text, dropping the tags of HTML text.
+func moodlePlainText(text, format string) string {
+	if format == "html" || format == "" {
+		text = htmlBreak.ReplaceAllString(text, " ")
+		text = html.UnescapeString(htmlTag.ReplaceAllString(text, ""))
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
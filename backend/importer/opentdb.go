@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/gclluch/TriviaApp-ReactGo/services"
+)
+
+// readOpenTDB reads questions saved from the Open Trivia Database: a whole API response, or
+// just its list of results. Answers may be HTML-encoded, as the API sends them by default.
+func readOpenTDB(r io.Reader) ([]parsedQuestion, Errors) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, Errors{{Message: err.Error()}}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := findResults(decoder); err != nil {
+		return nil, Errors{{Line: lineAt(data, decoder.InputOffset()), Message: err.Error()}}
+	}
+
+	var parsed []parsedQuestion
+	var errs Errors
+	for decoder.More() {
+		line := lineAt(data, decoder.InputOffset())
+		var apiQuestion models.APIQuestion
+		if err := decoder.Decode(&apiQuestion); err != nil {
+			// The decoder cannot find its footing again after malformed JSON.
+			return parsed, append(errs, LineError{Line: line, Message: err.Error()})
+		}
+		switch {
+		case apiQuestion.Question == "":
+			errs = append(errs, LineError{Line: line, Message: "question text is missing"})
+			continue
+		case apiQuestion.CorrectAnswer == "":
+			errs = append(errs, LineError{Line: line, Message: "correct_answer is missing"})
+			continue
+		}
+		if apiQuestion.Type == "" {
+			apiQuestion.Type = models.QuestionMultiple
+		}
+		question := services.FormatQuestions([]models.APIQuestion{apiQuestion})[0]
+		if question.QuestionType() == models.QuestionBoolean && question.CorrectIndex < 0 {
+			errs = append(errs, LineError{Line: line, Message: fmt.Sprintf("correct_answer %q is neither True nor False", apiQuestion.CorrectAnswer)})
+			continue
+		}
+		parsed = append(parsed, parsedQuestion{line: line, question: question})
+	}
+	return parsed, errs
+}
+
+// findResults advances the decoder into the list of questions, inside the results of an API
+// response if the file holds a whole response.
+func findResults(decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == json.Delim('[') {
+		return nil
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("expected an API response or a list of questions")
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "results":
+			if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+				return fmt.Errorf("results must be a list of questions")
+			}
+			return nil
+		case "response_code":
+			var code int
+			if err := decoder.Decode(&code); err != nil {
+				return err
+			}
+			if code != 0 {
+				return fmt.Errorf("the saved response failed with response_code %d", code)
+			}
+		default:
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("the response has no results")
+}
+
+// lineAt returns the line of the next value after offset in data, skipping the white space
+// and separators before it.
+func lineAt(data []byte, offset int64) int {
+	for offset < int64(len(data)) && bytes.IndexByte([]byte(" \t\r\n,:"), data[offset]) >= 0 {
+		offset++
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlQuestion is a question in a YAML file. It has the fields of the CSV columns, with
+// lists written as YAML lists:
+//
+//   - type: multiselect
+//     question: Which are prime?
+//     options: [2, 4, 5, 9]
+//     answer: [2, 5]
+//   - question: What is the capital of France?
+//     options: [Paris, Lyon, Nice]
+//     answer: Paris
+type yamlQuestion struct {
+	Type       string     `yaml:"type"`
+	Question   string     `yaml:"question"`
+	Options    []string   `yaml:"options"`
+	Answer     answerList `yaml:"answer"`
+	Unit       string     `yaml:"unit"`
+	Category   string     `yaml:"category"`
+	Difficulty string     `yaml:"difficulty"`
+	Tags       []string   `yaml:"tags"`
+}
+
+// answerList is one answer or a list of them.
+type answerList []string
+
+func (a *answerList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*a = answerList{value.Value}
+		return nil
+	}
+	var answers []string
+	if err := value.Decode(&answers); err != nil {
+		return err
+	}
+	*a = answers
+	return nil
+}
+
+// readYAML reads questions from a YAML list.
+func readYAML(r io.Reader) ([]parsedQuestion, Errors) {
+	var document yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, Errors{{Message: err.Error()}}
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.SequenceNode {
+		return nil, Errors{{Line: document.Line, Message: "expected a list of questions"}}
+	}
+
+	var parsed []parsedQuestion
+	var errs Errors
+	for _, item := range document.Content[0].Content {
+		if item.Kind != yaml.MappingNode {
+			errs = append(errs, LineError{Line: item.Line, Message: "expected a question with named fields"})
+			continue
+		}
+		if field := unknownField(item); field != "" {
+			errs = append(errs, LineError{Line: item.Line, Message: fmt.Sprintf("unknown field %q", field)})
+			continue
+		}
+		var entry yamlQuestion
+		if err := item.Decode(&entry); err != nil {
+			errs = append(errs, LineError{Line: item.Line, Message: err.Error()})
+			continue
+		}
+		question, err := record{
+			Type:       entry.Type,
+			Question:   entry.Question,
+			Options:    entry.Options,
+			Answers:    entry.Answer,
+			Unit:       entry.Unit,
+			Category:   entry.Category,
+			Difficulty: entry.Difficulty,
+			Tags:       entry.Tags,
+		}.toQuestion()
+		if err != nil {
+			errs = append(errs, LineError{Line: item.Line, Message: err.Error()})
+			continue
+		}
+		parsed = append(parsed, parsedQuestion{line: item.Line, question: question})
+	}
+	return parsed, errs
+}
+
+// unknownField returns the first field of a question that is not a CSV column, if any.
+func unknownField(item *yaml.Node) string {
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if key := item.Content[i].Value; !csvColumns[key] {
+			return key
+		}
+	}
+	return ""
+}
@@ -1,15 +1,36 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/gclluch/TriviaApp-ReactGo/bank"
+	"github.com/gclluch/TriviaApp-ReactGo/bus"
+	"github.com/gclluch/TriviaApp-ReactGo/challenge"
+	"github.com/gclluch/TriviaApp-ReactGo/chat"
+	"github.com/gclluch/TriviaApp-ReactGo/daily"
 	"github.com/gclluch/TriviaApp-ReactGo/game"
+	"github.com/gclluch/TriviaApp-ReactGo/grading"
 	"github.com/gclluch/TriviaApp-ReactGo/handlers"
+	"github.com/gclluch/TriviaApp-ReactGo/history"
+	"github.com/gclluch/TriviaApp-ReactGo/identity"
+	"github.com/gclluch/TriviaApp-ReactGo/media"
+	"github.com/gclluch/TriviaApp-ReactGo/origin"
+	"github.com/gclluch/TriviaApp-ReactGo/services"
 	"github.com/gclluch/TriviaApp-ReactGo/store"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
 )
 
@@ -19,23 +40,87 @@ func main() {
 	// Load configurations
 	loadConfig()
 
+	// Subcommands run instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
+
 	// Initialize the Gin router with CORS configuration
 	router := setupRouter()
 
+	// Background work stops when the server is interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Initialize the game server with preloaded questions
-	gameServer := initializeGameServer()
+	gameServer := initializeGameServer(ctx)
 
 	// Register HTTP and WebSocket handlers
 	handlers.RegisterHandlers(router, gameServer)
 
-	// Start the HTTP server
-	startServer(router)
+	// Start the HTTP server and serve until interrupted
+	startServer(ctx, router)
+
+	// Stop claiming sessions so other replicas can take them over
+	gameServer.Store.Close()
+	if gameServer.Store.History != nil {
+		gameServer.Store.History.Flush()
+	}
+
+	// Let background work save its state
+	background.Wait()
 }
 
+// background tracks the work started alongside the server, which main waits for on shutdown.
+var background sync.WaitGroup
+
 func loadConfig() {
 	viper.SetDefault("PORT", "8080")
-	// viper.SetDefault("QUESTIONS_FILE", "questions.json")
-	viper.AutomaticEnv() // Read from environment variables
+	viper.SetDefault("RECONNECT_GRACE", "30s")
+	viper.SetDefault("BUS_DRIVER", "local") // "local" for a single replica, "redis" to share sessions across replicas
+	viper.SetDefault("REDIS_URL", "redis://localhost:6379/0")
+	viper.SetDefault("NODE_ID", uuid.New().String())
+	viper.SetDefault("ALLOWED_ORIGINS", "http://localhost:3000") // Comma-separated; "*" allows any origin
+	viper.SetDefault("ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,HEAD,OPTIONS")
+	viper.SetDefault("ALLOW_CREDENTIALS", false)
+	viper.SetDefault("CHAT_HISTORY_SIZE", 50)
+	viper.SetDefault("CHAT_RATE_LIMIT", 5) // Chat messages and reactions per player per CHAT_RATE_WINDOW
+	viper.SetDefault("CHAT_RATE_WINDOW", "10s")
+	viper.SetDefault("CHAT_BLOCKED_WORDS", "")                 // Comma-separated words masked in chat
+	viper.SetDefault("QUESTIONS_FILE", "triviaQuestions.json") // Local question bank for the daily challenge and question types OpenTDB lacks
+	viper.SetDefault("DAILY_QUESTIONS", 10)
+	viper.SetDefault("DAILY_SECRET", "")                               // Mixed into each day's seed so upcoming sets cannot be predicted
+	viper.SetDefault("DAILY_ATTEMPTS_FILE", "data/dailyAttempts.json") // Keeps daily attempts across restarts; empty keeps them in memory
+	viper.SetDefault("USER_SECRET", "")                                // Signs user tokens; replicas must share it
+	viper.SetDefault("USER_ISSUE_LIMIT", 5)                            // User IDs one client address may be issued per USER_ISSUE_WINDOW; 0 for no limit
+	viper.SetDefault("USER_ISSUE_WINDOW", "24h")
+	viper.SetDefault("CHALLENGE_TTL", "72h")
+	viper.SetDefault("CHALLENGE_FILE", "data/challenges.json")          // Keeps challenges across restarts; empty keeps them in memory
+	viper.SetDefault("TEXT_ANSWER_TOLERANCE", grading.DefaultTolerance) // Edits a typed answer may be off by
+	viper.SetDefault("MEDIA_DIR", "data/media")                         // Images and sound clips attached to questions
+	viper.SetDefault("MEDIA_SECRET", "")                                // Seals media URLs; replicas must share it
+	viper.SetDefault("MEDIA_URL_TTL", media.DefaultTTL)                 // How long a media URL stays valid
+	viper.SetDefault("MEDIA_BASE_URL", "")                              // Origin clients reach the API at; empty for relative media URLs
+	viper.SetDefault("ADMIN_TOKEN", "")                                 // Bearer token for the admin API; empty disables it
+	viper.SetDefault("QUESTION_POOL_SIZE", 100)                         // OpenTDB questions prefetched per category and difficulty; 0 fetches on demand
+	viper.SetDefault("QUESTION_POOL_REFILL_BELOW", 40)                  // Pools holding fewer questions are refilled
+	viper.SetDefault("QUESTION_POOL_FILE", "data/questionPool.json")    // Keeps prefetched questions across restarts; empty keeps them in memory
+	viper.SetDefault("QUESTION_POOL_PAUSE", "5s")                       // Wait between OpenTDB requests; it allows one per 5 seconds
+	viper.SetDefault("HISTORY_FILE", "data/answeredQuestions.jsonl")    // Questions each returning player has answered; empty keeps none
+	viper.SetDefault("HISTORY_MAX_QUESTIONS", 1000)                     // Most recent answered questions remembered per player; 0 for all
+	viper.SetDefault("HISTORY_MAX_AGE", "2160h")                        // Answered questions are forgotten after this long; 0 for never
+	viper.AutomaticEnv()                                                // Read from environment variables
+}
+
+// configList splits a comma-separated configuration value into trimmed, non-empty entries.
+func configList(key string) []string {
+	var entries []string
+	for _, entry := range strings.Split(viper.GetString(key), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
 }
 
 func setupRouter() *gin.Engine {
@@ -44,24 +129,169 @@ func setupRouter() *gin.Engine {
 	router.Use(gin.Recovery())
 
 	config := cors.DefaultConfig()
-	config.AllowAllOrigins = true
+	config.AllowOriginFunc = originPolicy().Allowed
+	config.AllowMethods = configList("ALLOWED_METHODS")
+	config.AllowCredentials = viper.GetBool("ALLOW_CREDENTIALS")
 	router.Use(cors.New(config))
 	return router
 }
 
-func initializeGameServer() *game.GameServer {
-	// questions, err := services.LoadQuestions(viper.GetString("QUESTIONS_FILE"))
-	// if err != nil {
-	// 	log.Fatalf("Failed to load questions: %v", err)
-	// }
-	sessionStore := store.NewSessionStore()
-	return game.NewGameServer(sessionStore)
+// originPolicy builds the allowed-origin policy shared by CORS and the WebSocket upgrader.
+func originPolicy() *origin.Policy {
+	return origin.NewPolicy(configList("ALLOWED_ORIGINS"))
 }
 
-func startServer(router *gin.Engine) {
+func initializeGameServer(ctx context.Context) *game.GameServer {
+	sessionStore := initializeSessionStore()
+	sessionStore.Chat = chat.Config{
+		HistorySize: viper.GetInt("CHAT_HISTORY_SIZE"),
+		RateLimit:   viper.GetInt("CHAT_RATE_LIMIT"),
+		RateWindow:  viper.GetDuration("CHAT_RATE_WINDOW"),
+		Moderator:   chat.WordFilter{Words: configList("CHAT_BLOCKED_WORDS")},
+	}
+	sessionStore.Tolerance = viper.GetInt("TEXT_ANSWER_TOLERANCE")
+	sessionStore.Media = initializeMedia()
+	sessionStore.History = initializeHistory()
+	gameServer := game.NewGameServer(sessionStore, originPolicy())
+	gameServer.ReconnectGrace = viper.GetDuration("RECONNECT_GRACE")
+	sessionStore.Grace = gameServer.ReconnectGrace
+	gameServer.Challenges = challenge.NewChallenges(viper.GetDuration("CHALLENGE_TTL"))
+	gameServer.Challenges.Path = viper.GetString("CHALLENGE_FILE")
+	if err := gameServer.Challenges.Load(); err != nil {
+		log.Fatalf("Failed to load challenges: %v", err)
+	}
+
+	questionBank, err := bank.Open(viper.GetString("QUESTIONS_FILE"))
+	if err != nil {
+		log.Fatalf("Failed to load question bank: %v", err)
+	}
+	gameServer.Bank = questionBank
+	gameServer.AdminToken = viper.GetString("ADMIN_TOKEN")
+	gameServer.Identity = initializeIdentity()
+	// Question types the Open Trivia Database lacks, such as free text, come from the bank.
+	sessionStore.Questions = services.RoutedProvider{
+		Remote: initializeQuestionPool(ctx),
+		Local:  services.BankProvider{Bank: questionBank},
+	}
+	// Daily sets are drawn from the bank as it stood at startup, so a day's set never changes.
+	gameServer.Daily = daily.NewChallenges(daily.Config{
+		Bank:   questionBank.Questions(),
+		Size:   viper.GetInt("DAILY_QUESTIONS"),
+		Secret: viper.GetString("DAILY_SECRET"),
+		Path:   viper.GetString("DAILY_ATTEMPTS_FILE"),
+	})
+	if err := gameServer.Daily.Load(); err != nil {
+		log.Fatalf("Failed to load daily challenge attempts: %v", err)
+	}
+	if err := gameServer.Daily.CheckBank(); err != nil {
+		log.Printf("Daily challenge: %v; add questions to QUESTIONS_FILE or lower DAILY_QUESTIONS", err)
+	}
+	return gameServer
+}
+
+// initializeIdentity creates the issuer of players' user tokens.
+func initializeIdentity() *identity.Issuer {
+	issuer, err := identity.NewIssuer([]byte(viper.GetString("USER_SECRET")))
+	if err != nil {
+		log.Fatalf("Failed to initialize user tokens: %v", err)
+	}
+	issuer.IssueLimit = viper.GetInt("USER_ISSUE_LIMIT")
+	issuer.IssueWindow = viper.GetDuration("USER_ISSUE_WINDOW")
+	if viper.GetString("USER_SECRET") == "" {
+		log.Printf("USER_SECRET is not set; user tokens will not survive a restart or work across replicas")
+	}
+	return issuer
+}
+
+// initializeQuestionPool starts prefetching Open Trivia Database questions until ctx is
+// cancelled, unless the pool is configured off.
+func initializeQuestionPool(ctx context.Context) services.QuestionProvider {
+	size := viper.GetInt("QUESTION_POOL_SIZE")
+	if size <= 0 {
+		return services.OpenTDBProvider{}
+	}
+	pool := services.NewPool(services.OpenTDBProvider{}, size, viper.GetInt("QUESTION_POOL_REFILL_BELOW"), viper.GetString("QUESTION_POOL_FILE"))
+	pool.Pause = viper.GetDuration("QUESTION_POOL_PAUSE")
+	if err := pool.Load(); err != nil {
+		log.Printf("Starting with empty question pools: %v", err)
+	}
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		pool.Run(ctx)
+	}()
+	return pool
+}
+
+// initializeHistory opens the record of the questions each returning player has answered,
+// unless it is configured off.
+func initializeHistory() *history.History {
+	path := viper.GetString("HISTORY_FILE")
+	if path == "" {
+		return nil
+	}
+	answered, err := history.Open(path, viper.GetInt("HISTORY_MAX_QUESTIONS"), viper.GetDuration("HISTORY_MAX_AGE"))
+	if err != nil {
+		log.Fatalf("Failed to load question history: %v", err)
+	}
+	return answered
+}
+
+// initializeMedia opens the question media store and the library signing URLs for it.
+func initializeMedia() *media.Library {
+	library, err := media.NewLibrary(
+		media.FileStore{Dir: viper.GetString("MEDIA_DIR")},
+		[]byte(viper.GetString("MEDIA_SECRET")),
+		viper.GetDuration("MEDIA_URL_TTL"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to initialize question media: %v", err)
+	}
+	if viper.GetString("MEDIA_SECRET") == "" {
+		log.Printf("MEDIA_SECRET is not set; media URLs will not survive a restart or work across replicas")
+	}
+	library.BaseURL = strings.TrimSuffix(viper.GetString("MEDIA_BASE_URL"), "/")
+	return library
+}
+
+// initializeSessionStore builds the session store on the configured broadcast bus.
+func initializeSessionStore() *store.SessionStore {
+	switch driver := viper.GetString("BUS_DRIVER"); driver {
+	case "local":
+		return store.NewSessionStore()
+	case "redis":
+		options, err := redis.ParseURL(viper.GetString("REDIS_URL"))
+		if err != nil {
+			log.Fatalf("Invalid REDIS_URL: %v", err)
+		}
+		client := redis.NewClient(options)
+		return store.NewDistributedSessionStore(
+			viper.GetString("NODE_ID"),
+			bus.NewRedisBus(client),
+			bus.NewRedisLocker(client),
+		)
+	default:
+		log.Fatalf("Unknown BUS_DRIVER: %s", driver)
+		return nil
+	}
+}
+
+// startServer serves HTTP until ctx is cancelled, then gives open requests a moment to finish.
+func startServer(ctx context.Context, router *gin.Engine) {
 	port := viper.GetString("PORT")
+	server := &http.Server{Addr: ":" + port, Handler: router}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to shut down server: %v", err)
+		}
+	}()
+
 	log.Printf("Server starting on port %s\n", port)
-	if err := router.Run(":" + port); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Failed to run server: %v", err)
 	}
+	log.Println("Server stopped")
 }
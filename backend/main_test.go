@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/gclluch/TriviaApp-ReactGo/handlers"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
 var testServer *httptest.Server
@@ -19,9 +21,9 @@ func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	loadConfig() // Assuming this sets up your environment as needed
 
-	router := setupRouter()                       // Use the setup from your actual application
-	gameServer := initializeGameServer()          // Initialize your game server with configurations
-	handlers.RegisterHandlers(router, gameServer) // Register the same handlers as your application
+	router := setupRouter()                                  // Use the setup from your actual application
+	gameServer := initializeGameServer(context.Background()) // Initialize your game server with configurations
+	handlers.RegisterHandlers(router, gameServer)            // Register the same handlers as your application
 
 	testServer = httptest.NewServer(router) // Use the configured router for testing
 
@@ -56,6 +58,58 @@ func TestStartGameHandler(t *testing.T) {
 	}
 }
 
+func TestCORSRejectsUnknownOrigin(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/leaderboard", testServer.URL), nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected status Forbidden for unknown origin; got %v", resp.Status)
+	}
+}
+
+func TestCORSAllowsConfiguredOrigin(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/leaderboard", testServer.URL), nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status OK for configured origin; got %v", resp.Status)
+	}
+	if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "http://localhost:3000" {
+		t.Errorf("Expected Access-Control-Allow-Origin to echo the origin; got %q", got)
+	}
+}
+
+func TestWebSocketRejectsUnknownOrigin(t *testing.T) {
+	wsURL := "ws" + strings.TrimPrefix(testServer.URL, "http") + "/ws"
+
+	header := http.Header{"Origin": []string{"https://evil.example.com"}}
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+	if err == nil {
+		conn.Close()
+		t.Fatalf("Expected WebSocket handshake from unknown origin to fail")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected status Forbidden for unknown origin; got %v", resp)
+	}
+
+	header = http.Header{"Origin": []string{"http://localhost:3000"}}
+	conn, _, err = websocket.DefaultDialer.Dial(wsURL, header)
+	if err != nil {
+		t.Fatalf("Expected WebSocket handshake from configured origin to succeed: %v", err)
+	}
+	conn.Close()
+}
+
 // func TestSinglePlayerGame(t *testing.T) {
 // 	// Simulate a single player game
 
@@ -0,0 +1,195 @@
+// Package media keeps the images and sound clips attached to questions and serves them
+// through sealed, expiring URLs, so players can fetch a question's media only once it is asked.
+package media
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// Path is where the media handler is mounted; media URLs point below it.
+const Path = "/media/"
+
+// DefaultTTL is how long a media URL stays valid when none is configured.
+const DefaultTTL = 10 * time.Minute
+
+var (
+	ErrNotFound   = errors.New("media not found")
+	ErrInvalidKey = errors.New("invalid media key")
+	ErrExpired    = errors.New("media URL has expired")
+	ErrInvalidURL = errors.New("media URL is invalid")
+)
+
+// validKey matches keys that are safe as file names: no separators, and no leading dot.
+var validKey = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// ValidKey reports whether key can name a file in a media store.
+func ValidKey(key string) bool {
+	return validKey.MatchString(key)
+}
+
+// Store keeps media files by key.
+type Store interface {
+	Put(name string, content io.Reader) (string, error) // Saves content and returns the key it is kept under.
+	Open(key string) (io.ReadSeekCloser, error)         // Opens a file for reading; ErrNotFound if there is none.
+	Delete(key string) error
+}
+
+// FileStore is a Store keeping media files in a local directory. Files are named by the
+// SHA-256 of their content and the extension of the name they were saved with, so a key
+// says nothing about what the file shows.
+type FileStore struct {
+	Dir string
+}
+
+// Put saves content under a key derived from it. Saving the same content twice keeps one file.
+func (s FileStore) Put(name string, content io.Reader) (string, error) {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return "", err
+	}
+	temp, err := os.CreateTemp(s.Dir, ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(temp.Name())
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(temp, hash), content); err != nil {
+		temp.Close()
+		return "", err
+	}
+	if err := temp.Close(); err != nil {
+		return "", err
+	}
+
+	key := hex.EncodeToString(hash.Sum(nil)) + strings.ToLower(filepath.Ext(name))
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	if err := os.Rename(temp.Name(), filepath.Join(s.Dir, key)); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Open opens the file kept under key.
+func (s FileStore) Open(key string) (io.ReadSeekCloser, error) {
+	if !ValidKey(key) {
+		return nil, ErrInvalidKey
+	}
+	file, err := os.Open(filepath.Join(s.Dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Delete removes the file kept under key.
+func (s FileStore) Delete(key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	err := os.Remove(filepath.Join(s.Dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Library hands out URLs for the files in a Store. Each URL names its file by a token sealed
+// with the secret for that sending alone, so neither the key nor the file's reuse across
+// questions and games shows in it. Replicas sharing a store must share the secret.
+type Library struct {
+	Store   Store
+	Secret  []byte        // Key sealing URL tokens.
+	TTL     time.Duration // How long a URL stays valid.
+	BaseURL string        // Origin the API is reached at, e.g. "https://api.example.com"; empty for relative URLs.
+}
+
+// NewLibrary serves media from store. Without a secret, one is generated, and URLs handed
+// out by this process stop working when it restarts.
+func NewLibrary(store Store, secret []byte, ttl time.Duration) (*Library, error) {
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Library{Store: store, Secret: secret, TTL: ttl}, nil
+}
+
+// URL returns a URL for the file kept under key that is valid for the library's TTL. Every
+// call returns a different URL, even for the same file.
+func (l *Library) URL(key string) string {
+	return l.urlUntil(key, time.Now().Add(l.TTL))
+}
+
+// urlUntil returns a URL for the file kept under key that is valid until expires.
+func (l *Library) urlUntil(key string, expires time.Time) string {
+	aead := l.aead()
+	sealed := make([]byte, aead.NonceSize(), aead.NonceSize()+8+len(key)+aead.Overhead())
+	if _, err := rand.Read(sealed); err != nil {
+		panic(err) // crypto/rand does not fail on supported platforms.
+	}
+	plain := binary.BigEndian.AppendUint64(nil, uint64(expires.Unix()))
+	sealed = aead.Seal(sealed, sealed, append(plain, key...), nil)
+	return l.BaseURL + Path + base64.RawURLEncoding.EncodeToString(sealed)
+}
+
+// Resolve returns the key of the file a URL's token grants and when the URL expires.
+func (l *Library) Resolve(token string) (string, time.Time, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	aead := l.aead()
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", time.Time{}, ErrInvalidURL
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil || len(plain) < 8 {
+		return "", time.Time{}, ErrInvalidURL
+	}
+	expires := time.Unix(int64(binary.BigEndian.Uint64(plain)), 0)
+	if time.Now().After(expires) {
+		return "", time.Time{}, ErrExpired
+	}
+	return string(plain[8:]), expires, nil
+}
+
+// aead returns the cipher sealing URL tokens, keyed by a hash of the secret.
+func (l *Library) aead() cipher.AEAD {
+	key := sha256.Sum256(l.Secret)
+	block, _ := aes.NewCipher(key[:]) // A 32-byte key is always valid.
+	aead, _ := cipher.NewGCM(block)
+	return aead
+}
+
+// Attach returns copies of a question's media ready to send to players: each with a fresh
+// URL and without its key. A nil library attaches nothing.
+func (l *Library) Attach(attachments []models.Media) []models.Media {
+	if l == nil || len(attachments) == 0 {
+		return nil
+	}
+	signed := make([]models.Media, len(attachments))
+	for i, attachment := range attachments {
+		attachment.URL = l.URL(attachment.Key)
+		attachment.Key = ""
+		signed[i] = attachment
+	}
+	return signed
+}
@@ -0,0 +1,69 @@
+package media
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignedURLsOpenStoredMediaUntilTheyExpire(t *testing.T) {
+	store := FileStore{Dir: t.TempDir()}
+	key, err := store.Put("France.PNG", strings.NewReader("blue white red"))
+	if err != nil {
+		t.Fatalf("Failed to store media: %v", err)
+	}
+	if strings.Contains(key, "France") || !strings.HasSuffix(key, ".png") {
+		t.Errorf("Expected a content-derived key with the file's extension; got %q", key)
+	}
+	if again, _ := store.Put("other.png", strings.NewReader("blue white red")); again != key {
+		t.Errorf("Expected the same content to be kept under the same key; got %q and %q", key, again)
+	}
+
+	library, err := NewLibrary(store, []byte("secret"), time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to create library: %v", err)
+	}
+	first, again := library.URL(key), library.URL(key)
+	if !strings.HasPrefix(first, Path) || strings.Contains(first, key) || first == again {
+		t.Errorf("Expected a different URL hiding the key for every sending; got %q and %q", first, again)
+	}
+	token := strings.TrimPrefix(first, Path)
+	if resolved, expires, err := library.Resolve(token); err != nil || resolved != key || time.Until(expires) > time.Minute {
+		t.Errorf("Expected a fresh URL to resolve to %q for a minute; got %q until %v, %v", key, resolved, expires, err)
+	}
+	tampered := []byte(token)
+	if tampered[len(tampered)/2] == 'A' {
+		tampered[len(tampered)/2] = 'B'
+	} else {
+		tampered[len(tampered)/2] = 'A'
+	}
+	if _, _, err := library.Resolve(string(tampered)); err != ErrInvalidURL {
+		t.Errorf("Expected a tampered URL to be rejected; got %v", err)
+	}
+	other, _ := NewLibrary(store, []byte("another secret"), time.Minute)
+	if _, _, err := other.Resolve(token); err != ErrInvalidURL {
+		t.Errorf("Expected a URL from another secret to be rejected; got %v", err)
+	}
+
+	expired := strings.TrimPrefix(library.urlUntil(key, time.Now().Add(-time.Second)), Path)
+	if _, _, err := library.Resolve(expired); err != ErrExpired {
+		t.Errorf("Expected an expired URL to be rejected; got %v", err)
+	}
+
+	file, err := store.Open(key)
+	if err != nil {
+		t.Fatalf("Failed to open media: %v", err)
+	}
+	content, _ := io.ReadAll(file)
+	file.Close()
+	if string(content) != "blue white red" {
+		t.Errorf("Unexpected media content %q", content)
+	}
+	if _, err := store.Open("../secret"); err != ErrInvalidKey {
+		t.Errorf("Expected a path outside the store to be rejected; got %v", err)
+	}
+	if _, err := store.Open("missing.png"); err != ErrNotFound {
+		t.Errorf("Expected a missing file to be reported; got %v", err)
+	}
+}
@@ -1,27 +1,159 @@
 package models
 
-// Question represents a single trivia question with multiple choice answers.
+import "time"
+
+// Question types. Multiple choice and true/false are named as the Open Trivia Database names them.
+const (
+	QuestionMultiple    = "multiple"    // One correct answer among several options.
+	QuestionBoolean     = "boolean"     // True or false; Options are always BooleanOptions.
+	QuestionFreeText    = "text"        // Typed answers matched against Answers; there are no Options.
+	QuestionNumeric     = "numeric"     // Players guess a number; the closest guesses to Value score.
+	QuestionMultiSelect = "multiselect" // Every option in CorrectIndexes must be picked; partial picks earn partial credit.
+	QuestionOrdering    = "ordering"    // Options must be put in the order given by CorrectOrder, as in a chronology.
+)
+
+// QuestionMixed asks for both multiple choice and true/false questions in GameOptions.
+const QuestionMixed = "mixed"
+
+// BooleanOptions are the options of every true/false question, in this order.
+var BooleanOptions = []string{"True", "False"}
+
+// Question represents a single trivia question of any of the question types.
 type Question struct {
-	ID           string   `json:"id"`           // Unique identifier for the question
-	QuestionText string   `json:"questionText"` // The text of the question
-	Options      []string `json:"options"`      // Available answers to the question
-	CorrectIndex int      `json:"correctIndex"` // The index of the correct answer in the Options slice
+	ID             string   `json:"id"`                       // Unique identifier for the question
+	Type           string   `json:"type"`                     // One of the question types; empty means multiple
+	QuestionText   string   `json:"questionText"`             // The text of the question
+	Options        []string `json:"options"`                  // Available answers to the question
+	CorrectIndex   int      `json:"correctIndex"`             // The index of the correct answer in the Options slice
+	Answers        []string `json:"answers,omitempty"`        // Accepted answers to a free-text question, canonical first
+	Value          *float64 `json:"value,omitempty"`          // True value of a numeric question
+	Unit           string   `json:"unit,omitempty"`           // Unit a numeric question is answered in, e.g. "metres"
+	CorrectIndexes []int    `json:"correctIndexes,omitempty"` // Indexes of every correct option of a multi-select question
+	CorrectOrder   []int    `json:"correctOrder,omitempty"`   // Option indexes of an ordering question, in the correct order
+	Media          []Media  `json:"media,omitempty"`          // Images or sound clips the question is about
+	Category       string   `json:"category,omitempty"`       // Subject, e.g. "Geography"
+	Difficulty     string   `json:"difficulty,omitempty"`     // "easy", "medium" or "hard"; empty if unrated
+	Source         string   `json:"source,omitempty"`         // Where the question came from, e.g. "opentdb" or an import format
+	Tags           []string `json:"tags,omitempty"`           // Labels admins group questions by
+}
+
+// Media types.
+const (
+	MediaImage = "image"
+	MediaAudio = "audio"
+)
+
+// Media is an image or sound clip attached to a question, such as a flag or song to name.
+type Media struct {
+	Type string `json:"type"`          // MediaImage or MediaAudio
+	Key  string `json:"key,omitempty"` // Where the file is kept in the media store; never sent to players
+	Alt  string `json:"alt,omitempty"` // Description for players who cannot see or hear it, without giving the answer away
+	URL  string `json:"url,omitempty"` // Expiring URL players fetch the file from, different every time the question is sent
+}
+
+// PublicQuestion is the view of a question sent to players while it is being asked, without its answer.
+type PublicQuestion struct {
+	ID           string   `json:"id"`              // Unique identifier for the question
+	Type         string   `json:"type"`            // One of the question types
+	QuestionText string   `json:"questionText"`    // The text of the question
+	Options      []string `json:"options"`         // Available answers to the question
+	Unit         string   `json:"unit,omitempty"`  // Unit a numeric question is answered in
+	Media        []Media  `json:"media,omitempty"` // Attached media, with URLs for this sending
+}
+
+// Public returns the question without its answer. Media is left out, as it needs URLs
+// handed out when the question is sent.
+func (q Question) Public() PublicQuestion {
+	return PublicQuestion{ID: q.ID, Type: q.QuestionType(), QuestionText: q.QuestionText, Options: q.Options, Unit: q.Unit}
+}
+
+// SingleAnswer reports whether the question is answered by picking one option, as multiple
+// choice and true/false questions are.
+func (q Question) SingleAnswer() bool {
+	switch q.QuestionType() {
+	case QuestionMultiple, QuestionBoolean:
+		return true
+	}
+	return false
+}
+
+// NumericValue returns the true value of a numeric question, or 0 if it has none.
+func (q Question) NumericValue() float64 {
+	if q.Value == nil {
+		return 0
+	}
+	return *q.Value
+}
+
+// HasOption reports whether index picks one of the question's options: 0 or 1 for a
+// true/false question.
+func (q Question) HasOption(index int) bool {
+	return index >= 0 && index < len(q.Options)
+}
+
+// QuestionType returns the question's type, treating questions saved before types existed as multiple choice.
+func (q Question) QuestionType() string {
+	if q.Type == "" {
+		return QuestionMultiple
+	}
+	return q.Type
 }
 
 // AnswerSubmission represents the payload for a player's answer submission.
 type AnswerSubmission struct {
-	SessionID  string `json:"sessionId"`  // Identifier for the game session
-	PlayerID   string `json:"playerId"`   // Identifier for the player submitting the answer
-	QuestionID string `json:"questionId"` // Identifier for the question being answered
-	Answer     int    `json:"answer"`     // The index of the selected answer
+	SessionID   string   `json:"sessionId"`   // Identifier for the game session
+	ResumeToken string   `json:"resumeToken"` // Resume token of the player submitting the answer, empty for a single-player game
+	QuestionID  string   `json:"questionId"`  // Identifier for the question being answered
+	Answer      int      `json:"answer"`      // The index of the selected answer
+	Text        string   `json:"text"`        // The typed answer to a free-text question
+	Number      *float64 `json:"number"`      // The guess at a numeric question
+	Selections  []int    `json:"selections"`  // Option indexes picked for a multi-select question, or put in order for an ordering question
 }
 
 // Player represents a player in the game.
 type Player struct {
-	ID       string `json:"id"`       // Unique identifier for the player
-	Name     string `json:"name"`     // Name of the player
-	Score    int    `json:"score"`    // Current score of the player
-	Finished bool   `json:"finished"` // Whether the player has finished answering questions
+	ID           string          `json:"id"`                  // Unique identifier for the player
+	Name         string          `json:"name"`                // Name of the player
+	Score        int             `json:"score"`               // Current score of the player
+	Finished     bool            `json:"finished"`            // Whether the player has finished answering questions
+	ResumeToken  string          `json:"-"`                   // Secret used to rebind the player after a reconnect
+	UserID       string          `json:"-"`                   // Stable ID the player's client keeps across games; empty if unknown
+	Answered     map[string]bool `json:"-"`                   // Questions the player has submitted an answer for
+	Team         string          `json:"team,omitempty"`      // Team the player belongs to in team mode
+	Correct      int             `json:"correct"`             // Number of questions the player scored on
+	Points       map[string]int  `json:"-"`                   // Points earned per question ID
+	LastScoredAt time.Time       `json:"-"`                   // When the player last scored, used for tie-breaking
+	Eliminated   bool            `json:"eliminated"`          // Whether the player was knocked out in elimination mode
+	Placement    int             `json:"placement,omitempty"` // Final position in elimination mode; tied players share it
+}
+
+// GameOptions represents the payload for creating a game session.
+type GameOptions struct {
+	NumQuestions   int            `json:"numQuestions"`   // Number of questions in the session
+	TeamMode       bool           `json:"teamMode"`       // Whether players compete in teams
+	Teams          []string       `json:"teams"`          // Team names; defaults to two teams in team mode
+	TeamScoring    string         `json:"teamScoring"`    // How member scores combine: "sum", "average" or "best"
+	Mode           string         `json:"mode"`           // Game mode: "classic" (default), "buzzer" or "elimination"
+	BuzzWindow     int            `json:"buzzWindow"`     // Seconds a buzzer holder has to answer in buzzer mode
+	TimeLimit      int            `json:"timeLimit"`      // Seconds per question in server-paced modes
+	Lifelines      map[string]int `json:"lifelines"`      // Uses of each lifeline in single-player games, e.g. {"skip": 1}
+	SpectatorDelay int            `json:"spectatorDelay"` // Seconds spectators' events lag behind the live game
+	Rounds         []RoundOptions `json:"rounds"`         // Rounds to play in order; numQuestions is then their total
+	QuestionType   string         `json:"questionType"`   // "multiple" (default), "boolean", "text", "numeric", "multiselect", "ordering" or "mixed"
+	ReviewAnswers  bool           `json:"reviewAnswers"`  // Whether the host reviews borderline free-text answers
+	NumericScoring string         `json:"numericScoring"` // How numeric questions score: "rank" (default) or "error"
+	UserIDs        []string       `json:"userIds"`        // Stable IDs of the expected players; questions they have answered are avoided
+}
+
+// RoundOptions describes one round of a multi-round game.
+type RoundOptions struct {
+	Name         string `json:"name"`         // Name shown to players, e.g. "Final"
+	Category     int    `json:"category"`     // Open Trivia Database category ID; 0 for any
+	Difficulty   string `json:"difficulty"`   // "easy", "medium" or "hard"; empty for any
+	NumQuestions int    `json:"numQuestions"` // Number of questions in the round
+	TimeLimit    int    `json:"timeLimit"`    // Seconds per question; elimination mode only; 0 for the game's
+	Scoring      string `json:"scoring"`      // "standard" (default) or "double"
+	QuestionType string `json:"questionType"` // A question type as for the game, or "mixed"; defaults to the game's
 }
 
 // SessionRequest represents the payload for a request pertaining to a session.
@@ -0,0 +1,64 @@
+package origin
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Policy decides which browser origins may call the API and open WebSocket connections.
+// The same policy backs both CORS and the WebSocket upgrader so they cannot drift apart.
+type Policy struct {
+	allowAll  bool
+	allowed   map[string]bool
+	wildcards [][2]string // Prefix and suffix around the "*" of entries like "https://*.example.com".
+}
+
+// NewPolicy builds a policy from a list of origins. "*" allows every origin, and a single
+// "*" inside an entry matches any subdomain, e.g. "https://*.example.com".
+func NewPolicy(origins []string) *Policy {
+	policy := &Policy{allowed: make(map[string]bool)}
+	for _, entry := range origins {
+		entry = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(entry), "/"))
+		switch {
+		case entry == "":
+		case entry == "*":
+			policy.allowAll = true
+		case strings.Count(entry, "*") == 1:
+			prefix, suffix, _ := strings.Cut(entry, "*")
+			policy.wildcards = append(policy.wildcards, [2]string{prefix, suffix})
+		default:
+			policy.allowed[entry] = true
+		}
+	}
+	return policy
+}
+
+// Allowed reports whether a browser origin is permitted.
+func (p *Policy) Allowed(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if p.allowed[origin] {
+		return true
+	}
+	for _, wildcard := range p.wildcards {
+		if strings.HasPrefix(origin, wildcard[0]) && strings.HasSuffix(origin, wildcard[1]) &&
+			len(origin) > len(wildcard[0])+len(wildcard[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckRequest is a websocket.Upgrader CheckOrigin function. Requests without an Origin
+// header come from non-browser clients and same-host requests are not cross-origin, so
+// both are accepted, matching how the CORS middleware treats them.
+func (p *Policy) CheckRequest(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || origin == "http://"+r.Host || origin == "https://"+r.Host {
+		return true
+	}
+	return p.Allowed(origin)
+}
@@ -0,0 +1,44 @@
+package origin
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPolicyAllowed(t *testing.T) {
+	policy := NewPolicy([]string{"http://localhost:3000", "https://*.example.com"})
+
+	cases := map[string]bool{
+		"http://localhost:3000":     true,
+		"HTTP://LOCALHOST:3000":     true,
+		"https://quiz.example.com":  true,
+		"https://example.com":       false,
+		"https://evil.com":          false,
+		"http://localhost:3001":     false,
+		"https://quiz.example.com.": false,
+	}
+	for origin, want := range cases {
+		if got := policy.Allowed(origin); got != want {
+			t.Errorf("Allowed(%q) = %v; want %v", origin, got, want)
+		}
+	}
+}
+
+func TestCheckRequestAcceptsSameHostAndNonBrowserClients(t *testing.T) {
+	policy := NewPolicy(nil)
+
+	req := httptest.NewRequest("GET", "http://api.local/ws", nil)
+	if !policy.CheckRequest(req) {
+		t.Errorf("Requests without an Origin header should be accepted")
+	}
+
+	req.Header.Set("Origin", "http://api.local")
+	if !policy.CheckRequest(req) {
+		t.Errorf("Same-host requests should be accepted")
+	}
+
+	req.Header.Set("Origin", "https://evil.com")
+	if policy.CheckRequest(req) {
+		t.Errorf("Cross-origin requests should be rejected by an empty policy")
+	}
+}
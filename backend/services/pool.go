@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/dedupe"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// maxFetchAmount is the most questions the Open Trivia Database returns for one request.
+const maxFetchAmount = 50
+
+// Pool serves questions fetched ahead of time from a slower provider, such as the Open Trivia
+// Database, so sessions start without waiting on it. It keeps a pool for each category,
+// difficulty and type asked for, refills the pools that run low in the background, and saves
+// them to a file so a restart begins with them full. Each question is served once.
+type Pool struct {
+	sync.Mutex
+	Remote      QuestionProvider // Provider the pools are filled from.
+	Size        int              // Questions a pool is filled to.
+	RefillBelow int              // A pool holding fewer questions than this is refilled.
+	Path        string           // File the pools are saved to; empty keeps them in memory only.
+	Pause       time.Duration    // Wait between fetches, to keep within the remote's rate limit.
+	pools       map[poolKey][]models.Question
+	changed     bool          // Whether the pools differ from the saved file.
+	refill      chan struct{} // Wakes the refill loop.
+	fetching    sync.Mutex    // Held while fetching from the remote, so fetches go one at a time.
+	lastFetch   time.Time     // When the last fetch from the remote finished; guarded by fetching.
+}
+
+// poolKey names a pool: the filters of the queries it serves.
+type poolKey struct {
+	Category   int    `json:"category"`
+	Difficulty string `json:"difficulty"`
+	Type       string `json:"type"`
+}
+
+// savedPool is a pool as written to the pool file.
+type savedPool struct {
+	poolKey
+	Questions []models.Question `json:"questions"`
+}
+
+// NewPool returns an empty pool of questions from remote. The pool default games draw from,
+// multiple choice questions of any category and difficulty, is always kept filled; others are
+// added as they are asked for.
+func NewPool(remote QuestionProvider, size, refillBelow int, path string) *Pool {
+	return &Pool{
+		Remote:      remote,
+		Size:        size,
+		RefillBelow: refillBelow,
+		Path:        path,
+		pools:       map[poolKey][]models.Question{{Type: models.QuestionMultiple}: nil},
+		refill:      make(chan struct{}, 1),
+	}
+}
+
+// Load restores the pools saved in the pool file. A missing file leaves the pools empty.
+func (p *Pool) Load() error {
+	if p.Path == "" {
+		return nil
+	}
+	bytes, err := os.ReadFile(p.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var saved []savedPool
+	if err := json.Unmarshal(bytes, &saved); err != nil {
+		return fmt.Errorf("%s: %w", p.Path, err)
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	for _, pool := range saved {
+		p.pools[pool.poolKey] = pool.Questions
+	}
+	return nil
+}
+
+// Run fills the pools that are low, then again each time questions are served. It returns
+// once ctx is cancelled, after saving the pools, so it is started in its own goroutine.
+func (p *Pool) Run(ctx context.Context) {
+	p.wake()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := p.save(); err != nil {
+				log.Printf("Failed to save question pool: %v", err)
+			}
+			return
+		case <-p.refill:
+			p.fillLowPools(ctx)
+			if err := p.save(); err != nil {
+				log.Printf("Failed to save question pool: %v", err)
+			}
+		}
+	}
+}
+
+// FetchQuestions serves query.Amount questions from the pool matching query that query does
+// not exclude, and fetches any shortfall from the remote straight away, though still within
+// its rate limit. Only if the remote comes up short too are excluded pool questions served.
+func (p *Pool) FetchQuestions(query Query) ([]models.Question, error) {
+	key := poolKey{Category: query.Category, Difficulty: query.Difficulty, Type: query.Type}
+
+	p.Lock()
+	pool, known := p.pools[key]
+	questions, rest := takeUnexcluded(pool, query.Exclude, query.Amount)
+	if known {
+		p.pools[key] = rest
+		p.changed = p.changed || len(questions) > 0
+	}
+	p.Unlock()
+	p.wake()
+
+	var fetchErr error
+	if missing := query.Amount - len(questions); missing > 0 {
+		p.fetching.Lock()
+		// A refill in flight may have filled this very pool while we waited for it.
+		if known {
+			p.Lock()
+			more, rest := takeUnexcluded(p.pools[key], query.Exclude, missing)
+			p.pools[key] = rest
+			p.changed = p.changed || len(more) > 0
+			p.Unlock()
+			questions = append(questions, more...)
+		}
+		var fetched []models.Question
+		if missing = query.Amount - len(questions); missing > 0 {
+			remoteQuery := query
+			remoteQuery.Amount = missing
+			fetched, fetchErr = p.fetchLocked(remoteQuery)
+		}
+		p.fetching.Unlock()
+		if !known && len(fetched) > 0 {
+			// Only queries the remote has questions for get a pool of their own.
+			p.Lock()
+			if _, known := p.pools[key]; !known {
+				p.pools[key] = nil
+			}
+			p.Unlock()
+			p.wake()
+		}
+		questions = dedupe.Unique(append(questions, fetched...))
+	}
+
+	if missing := query.Amount - len(questions); missing > 0 && known {
+		p.Lock()
+		seen, rest := takeUnexcluded(p.pools[key], nil, missing)
+		p.pools[key] = rest
+		p.changed = p.changed || len(seen) > 0
+		p.Unlock()
+		questions = dedupe.Unique(append(questions, seen...))
+	}
+	if fetchErr != nil {
+		if len(questions) == 0 {
+			return nil, fetchErr
+		}
+		log.Printf("Serving %d of %d questions; failed to fetch the rest: %v", len(questions), query.Amount, fetchErr)
+	}
+
+	for i := range questions {
+		questions[i].ID = fmt.Sprintf("%d", i+1)
+	}
+	return questions, nil
+}
+
+// wake asks the refill loop to check the pools, unless it is already due to.
+func (p *Pool) wake() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+// fetchRemote fetches questions from the remote. Every fetch holds fetching and goes through
+// fetchLocked, one at a time and at least Pause apart, so refills and shortfalls together
+// keep within the remote's rate limit.
+func (p *Pool) fetchRemote(query Query) ([]models.Question, error) {
+	p.fetching.Lock()
+	defer p.fetching.Unlock()
+
+	return p.fetchLocked(query)
+}
+
+// fetchLocked is fetchRemote for callers holding fetching.
+func (p *Pool) fetchLocked(query Query) ([]models.Question, error) {
+	time.Sleep(time.Until(p.lastFetch.Add(p.Pause)))
+	defer func() { p.lastFetch = time.Now() }()
+	return p.Remote.FetchQuestions(query)
+}
+
+// fillLowPools fetches questions for every pool below RefillBelow, up to Size, stopping early
+// if ctx is cancelled.
+func (p *Pool) fillLowPools(ctx context.Context) {
+	p.Lock()
+	var low []poolKey
+	for key, pool := range p.pools {
+		if len(pool) < p.RefillBelow {
+			low = append(low, key)
+		}
+	}
+	p.Unlock()
+
+	for _, key := range low {
+		if ctx.Err() != nil {
+			return
+		}
+		p.Lock()
+		amount := Min(p.Size-len(p.pools[key]), maxFetchAmount)
+		p.Unlock()
+		if amount <= 0 {
+			continue
+		}
+		fetched, err := p.fetchRemote(Query{Amount: amount, Category: key.Category, Difficulty: key.Difficulty, Type: key.Type})
+		if err != nil {
+			log.Printf("Failed to refill question pool %+v: %v", key, err)
+			continue
+		}
+
+		p.Lock()
+		index := dedupe.NewIndex()
+		for _, question := range p.pools[key] {
+			index.Add(question)
+		}
+		for _, question := range fetched {
+			if _, repeats := index.Find(question); !repeats {
+				index.Add(question)
+				p.pools[key] = append(p.pools[key], question)
+				p.changed = true
+			}
+		}
+		p.Unlock()
+	}
+}
+
+// save writes the pools to the pool file if they changed since it was last written,
+// replacing the old file only once the new one is complete.
+func (p *Pool) save() error {
+	p.Lock()
+	if p.Path == "" || !p.changed {
+		p.Unlock()
+		return nil
+	}
+	saved := make([]savedPool, 0, len(p.pools))
+	for key, pool := range p.pools {
+		saved = append(saved, savedPool{poolKey: key, Questions: pool})
+	}
+	p.changed = false
+	p.Unlock()
+
+	if err := p.write(saved); err != nil {
+		p.Lock()
+		p.changed = true
+		p.Unlock()
+		return err
+	}
+	return nil
+}
+
+// write saves pools to the pool file.
+func (p *Pool) write(saved []savedPool) error {
+	bytes, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.Path), 0o755); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(p.Path), ".pool-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(bytes); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), p.Path)
+}
+
+// takeUnexcluded takes up to amount questions that exclude does not name, in order. It
+// returns the questions taken and those left, in order.
+func takeUnexcluded(questions []models.Question, exclude map[string]bool, amount int) ([]models.Question, []models.Question) {
+	var served, rest []models.Question
+	for _, question := range questions {
+		if len(served) < amount && !exclude[dedupe.Key(question)] {
+			served = append(served, question)
+		} else {
+			rest = append(rest, question)
+		}
+	}
+	return served, rest
+}
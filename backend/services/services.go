@@ -8,13 +8,17 @@ import (
 	"math/rand"
 	"net/http"
 	"os"
-	"strconv"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/gclluch/TriviaApp-ReactGo/dedupe"
 	"github.com/gclluch/TriviaApp-ReactGo/models"
 )
 
-// LoadQuestions loads questions from a JSON file specified by filename.
+// LoadQuestions loads questions from a JSON file specified by filename. Questions without a
+// type are multiple choice; true/false questions may leave out their options, and ordering
+// questions their correct order if the options are listed in it.
 func LoadQuestions(filename string) ([]models.Question, error) {
 	bytes, err := os.ReadFile(filename) // Use os.ReadFile
 	if err != nil {
@@ -26,9 +30,117 @@ func LoadQuestions(filename string) ([]models.Question, error) {
 		return nil, err
 	}
 
+	for i := range questions {
+		if err := NormalizeQuestion(&questions[i]); err != nil {
+			return nil, fmt.Errorf("question %s: %w", questions[i].ID, err)
+		}
+	}
 	return questions, nil
 }
 
+// NormalizeQuestion fills in a question's type and any parts its type lets a bank leave out,
+// and checks that the correct answers are among the options.
+func NormalizeQuestion(question *models.Question) error {
+	question.Type = question.QuestionType()
+	question.Difficulty = strings.ToLower(strings.TrimSpace(question.Difficulty))
+	switch question.Difficulty {
+	case "", "easy", "medium", "hard":
+	default:
+		return fmt.Errorf("unknown difficulty %q", question.Difficulty)
+	}
+	question.Tags = normalizeTags(question.Tags)
+	for _, attachment := range question.Media {
+		if attachment.Type != models.MediaImage && attachment.Type != models.MediaAudio {
+			return fmt.Errorf("unknown media type %q", attachment.Type)
+		}
+		if attachment.Key == "" {
+			return fmt.Errorf("media needs a key")
+		}
+	}
+	switch question.Type {
+	case models.QuestionMultiple:
+		if len(question.Options) < 2 {
+			return fmt.Errorf("multiple choice questions need at least two options")
+		}
+	case models.QuestionFreeText:
+		if len(question.Answers) == 0 {
+			return fmt.Errorf("free-text questions need at least one accepted answer")
+		}
+		return nil
+	case models.QuestionNumeric:
+		if question.Value == nil {
+			return fmt.Errorf("numeric questions need a value")
+		}
+		return nil
+	case models.QuestionMultiSelect:
+		if len(question.Options) < 2 {
+			return fmt.Errorf("multi-select questions need at least two options")
+		}
+		if len(question.CorrectIndexes) == 0 {
+			return fmt.Errorf("multi-select questions need at least one correct option")
+		}
+		if !distinctIndexes(question.CorrectIndexes, len(question.Options)) {
+			return fmt.Errorf("correctIndexes %v must be distinct options", question.CorrectIndexes)
+		}
+		return nil
+	case models.QuestionOrdering:
+		if len(question.Options) < 2 {
+			return fmt.Errorf("ordering questions need at least two options")
+		}
+		if len(question.CorrectOrder) == 0 {
+			// Options listed in the right order need no separate answer.
+			question.CorrectOrder = make([]int, len(question.Options))
+			for i := range question.CorrectOrder {
+				question.CorrectOrder[i] = i
+			}
+		}
+		if len(question.CorrectOrder) != len(question.Options) || !distinctIndexes(question.CorrectOrder, len(question.Options)) {
+			return fmt.Errorf("correctOrder %v must place every option exactly once", question.CorrectOrder)
+		}
+		return nil
+	case models.QuestionBoolean:
+		if len(question.Options) == 0 {
+			question.Options = append([]string{}, models.BooleanOptions...)
+		}
+		if len(question.Options) != 2 || question.Options[0] != models.BooleanOptions[0] || question.Options[1] != models.BooleanOptions[1] {
+			return fmt.Errorf("true/false questions must have the options True and False")
+		}
+	default:
+		return fmt.Errorf("unknown question type %q", question.Type)
+	}
+	if question.CorrectIndex < 0 || question.CorrectIndex >= len(question.Options) {
+		return fmt.Errorf("correctIndex %d is not an option", question.CorrectIndex)
+	}
+	return nil
+}
+
+// normalizeTags lower-cases and sorts tags, dropping blanks and repeats.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// distinctIndexes reports whether indexes are all different and each below count.
+func distinctIndexes(indexes []int, count int) bool {
+	seen := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		if index < 0 || index >= count || seen[index] {
+			return false
+		}
+		seen[index] = true
+	}
+	return true
+}
+
 // ShuffleQuestions randomizes the order of questions.
 // Accepts a slice of Question structs and returns a new shuffled slice.
 func ShuffleQuestions(questions []models.Question) []models.Question {
@@ -38,16 +150,6 @@ func ShuffleQuestions(questions []models.Question) []models.Question {
 	return questions
 }
 
-func CheckAnswer(questions []models.Question, questionIDStr string, answerIndex int) (bool, bool) {
-	questionID, err := strconv.Atoi(questionIDStr) // Convert questionID from string to int
-	if err != nil || questionID < 0 || questionID >= len(questions) {
-		return false, false // QuestionID is not a valid index
-	}
-
-	question := questions[questionID]
-	return question.CorrectIndex == answerIndex, true
-}
-
 // Helper function to get the minimum of two integers
 func Min(a, b int) int {
 	if a < b {
@@ -67,22 +169,137 @@ func Clamp(value, min, max int) int {
 	return value
 }
 
-func FetchQuestions(amount int) ([]models.Question, error) {
-	apiQuestions, err := fetchAPIQuestions(amount)
+// Query selects the questions to fetch. Zero values leave a filter off.
+type Query struct {
+	Amount     int    // Number of questions.
+	Category   int    // Open Trivia Database category ID.
+	Difficulty string // "easy", "medium" or "hard".
+	Type       string // A models question type; empty for multiple choice or true/false.
+	// Exclude holds the dedupe.Key of questions to leave out, such as those the players have
+	// already seen. They are used only when too few other questions match.
+	Exclude map[string]bool
+}
+
+// QuestionProvider supplies formatted questions for new sessions and lifeline swaps.
+type QuestionProvider interface {
+	FetchQuestions(query Query) ([]models.Question, error)
+}
+
+// SourceOpenTDB is the Source of questions fetched from the Open Trivia Database.
+const SourceOpenTDB = "opentdb"
+
+// duplicateMargin sets how many extra questions are asked of the Open Trivia Database: one
+// for every duplicateMargin requested, and one more.
+const duplicateMargin = 5
+
+// OpenTDBProvider fetches questions from the Open Trivia Database.
+type OpenTDBProvider struct{}
+
+// FetchQuestions fetches and formats the questions matching query from the Open Trivia Database.
+// The database holds some questions more than once in different words, so only the first of
+// each is kept. A few more questions than query.Amount are asked for, up to the most one
+// request returns, to make up for those dropped, and the rest are trimmed. Fewer than
+// query.Amount are returned only if repeats take up more than the margin. When query
+// excludes questions, as many are asked for as one request returns, since there is no
+// telling how many of them the players have seen.
+func (OpenTDBProvider) FetchQuestions(query Query) ([]models.Question, error) {
+	padded := query
+	padded.Amount = min(query.Amount+query.Amount/duplicateMargin+1, max(query.Amount, maxFetchAmount))
+	if len(query.Exclude) > 0 {
+		padded.Amount = max(query.Amount, maxFetchAmount)
+	}
+	apiQuestions, err := fetchAPIQuestions(padded)
 	if err != nil {
 		return nil, err
 	}
 
-	return FormatQuestions(apiQuestions), nil
+	questions := pickFetched(dedupe.Unique(FormatQuestions(apiQuestions)), query)
+	for i := range questions {
+		questions[i].ID = fmt.Sprintf("%d", i+1)
+	}
+	return questions, nil
+}
+
+// pickFetched trims fetched questions to query.Amount, leaving out those query excludes
+// unless too few others were fetched.
+func pickFetched(questions []models.Question, query Query) []models.Question {
+	fresh, excluded := takeUnexcluded(questions, query.Exclude, query.Amount)
+	return append(fresh, excluded[:min(len(excluded), query.Amount-len(fresh))]...)
+}
+
+// QuestionSource supplies the questions of a local bank as they stand.
+type QuestionSource interface {
+	Questions() []models.Question
+}
+
+// BankProvider serves questions drawn at random from a local bank. Bank categories are names
+// rather than Open Trivia Database IDs, so only the type and difficulty filters apply, and
+// questions without a difficulty suit any.
+type BankProvider struct {
+	Bank QuestionSource
+}
+
+// FetchQuestions picks up to query.Amount questions of the requested type from the bank.
+func (p BankProvider) FetchQuestions(query Query) ([]models.Question, error) {
+	var matching []models.Question
+	for _, question := range p.Bank.Questions() {
+		if (query.Type == "" || question.QuestionType() == query.Type) &&
+			(query.Difficulty == "" || question.Difficulty == "" || question.Difficulty == query.Difficulty) {
+			matching = append(matching, question)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("no %s questions in the local bank", query.Type)
+	}
+	var unseen []models.Question
+	for _, question := range matching {
+		if !query.Exclude[dedupe.Key(question)] {
+			unseen = append(unseen, question)
+		}
+	}
+	if len(unseen) >= query.Amount {
+		matching = unseen
+	}
+	return SeededQuestions(matching, query.Amount, time.Now().UnixNano()), nil
+}
+
+// RoutedProvider fetches the question types the Open Trivia Database has from Remote, and
+// every other type from Local.
+type RoutedProvider struct {
+	Remote QuestionProvider
+	Local  QuestionProvider
+}
+
+// FetchQuestions fetches questions from whichever provider has the requested type.
+func (p RoutedProvider) FetchQuestions(query Query) ([]models.Question, error) {
+	switch query.Type {
+	case "", models.QuestionMultiple, models.QuestionBoolean:
+		return p.Remote.FetchQuestions(query)
+	}
+	return p.Local.FetchQuestions(query)
+}
+
+// FetchQuestions fetches and formats amount questions from the Open Trivia Database.
+func FetchQuestions(amount int) ([]models.Question, error) {
+	return OpenTDBProvider{}.FetchQuestions(Query{Amount: amount})
 }
 
 // fetchAPIQuestions fetches trivia questions from an external API.
-func fetchAPIQuestions(amount int) ([]models.APIQuestion, error) {
+func fetchAPIQuestions(query Query) ([]models.APIQuestion, error) {
 	var apiResponse struct {
 		Results []models.APIQuestion `json:"results"`
 	}
 
-	url := fmt.Sprintf("https://opentdb.com/api.php?amount=%d&type=multiple", amount)
+	url := fmt.Sprintf("https://opentdb.com/api.php?amount=%d", query.Amount)
+	if query.Type != "" {
+		url += "&type=" + query.Type
+	}
+	if query.Category > 0 {
+		url += fmt.Sprintf("&category=%d", query.Category)
+	}
+	if query.Difficulty != "" {
+		url += "&difficulty=" + query.Difficulty
+	}
 	resp, err := http.Get(url)
 	if err != nil {
 		return nil, err
@@ -101,42 +318,118 @@ func fetchAPIQuestions(amount int) ([]models.APIQuestion, error) {
 	return apiResponse.Results, nil
 }
 
-// FormatQuestions formats a slice of APIQuestion into a slice of Question.
+// FormatQuestions formats a slice of APIQuestion into a slice of Question, shuffling each
+// multiple choice question's options. True/false questions keep the options True and False.
 func FormatQuestions(apiQuestions []models.APIQuestion) []models.Question {
-	var questions []models.Question
+	return FormatQuestionsWith(apiQuestions, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
 
-	// Initialize the random seed outside the loop
-	rand.Seed(time.Now().UnixNano())
+// FormatQuestionsWith is FormatQuestions drawing the option order from rng, so a seeded
+// generator always produces the same questions.
+func FormatQuestionsWith(apiQuestions []models.APIQuestion, rng *rand.Rand) []models.Question {
+	var questions []models.Question
 
 	for i, apiQ := range apiQuestions {
 		// Decode HTML entities in question text
 		questionText := html.UnescapeString(apiQ.Question)
 
-		// Prepare options and decode HTML entities
-		options := make([]string, len(apiQ.IncorrectAnswers)+1)
-		for j, opt := range apiQ.IncorrectAnswers {
-			options[j] = html.UnescapeString(opt)
-		}
 		correctOption := html.UnescapeString(apiQ.CorrectAnswer)
-		options[len(options)-1] = correctOption
 
-		// Shuffle options
-		rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+		var options []string
+		if apiQ.Type == models.QuestionBoolean {
+			options = append([]string{}, models.BooleanOptions...)
+		} else {
+			// Prepare options and decode HTML entities
+			options = make([]string, len(apiQ.IncorrectAnswers)+1)
+			for j, opt := range apiQ.IncorrectAnswers {
+				options[j] = html.UnescapeString(opt)
+			}
+			options[len(options)-1] = correctOption
+
+			// Shuffle options
+			rng.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+		}
 
 		// Find the index of the correct answer after shuffling
 		correctIndex := findCorrectIndex(options, correctOption)
 
-		questions = append(questions, models.Question{
+		question := models.Question{
 			ID:           fmt.Sprintf("%d", i+1),
+			Type:         apiQ.Type,
 			QuestionText: questionText,
 			Options:      options,
 			CorrectIndex: correctIndex,
-		})
+			Category:     html.UnescapeString(apiQ.Category),
+			Difficulty:   apiQ.Difficulty,
+			Source:       SourceOpenTDB,
+		}
+		question.Type = question.QuestionType()
+		questions = append(questions, question)
 	}
 
 	return questions
 }
 
+// SeededQuestions picks amount questions from bank and shuffles the options of every question
+// type that has them in no fixed order, using only the given seed for randomness: the same
+// bank and seed always yield the same questions in the same order with the same option
+// order. Picked questions are renumbered from 1.
+func SeededQuestions(bank []models.Question, amount int, seed int64) []models.Question {
+	rng := rand.New(rand.NewSource(seed))
+
+	order := rng.Perm(len(bank))
+	questions := make([]models.Question, 0, Min(amount, len(bank)))
+	for i, index := range order[:Min(amount, len(bank))] {
+		question := bank[index]
+		question.ID = fmt.Sprintf("%d", i+1)
+		question.Type = question.QuestionType()
+		switch question.Type {
+		case models.QuestionMultiple, models.QuestionMultiSelect, models.QuestionOrdering:
+			shuffleOptions(&question, rng)
+		default:
+			question.Options = append([]string{}, question.Options...)
+		}
+		questions = append(questions, question)
+	}
+	return questions
+}
+
+// shuffleOptions shuffles a question's options with rng and moves its correct answers along
+// with them. The question's slices are replaced, so the bank it came from is left untouched.
+func shuffleOptions(question *models.Question, rng *rand.Rand) {
+	shuffled := make([]int, len(question.Options)) // shuffled[k] is the original index of option k.
+	for k := range shuffled {
+		shuffled[k] = k
+	}
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	options := make([]string, len(shuffled))
+	moved := make(map[int]int, len(shuffled)) // Original index to shuffled index.
+	for k, original := range shuffled {
+		options[k] = question.Options[original]
+		moved[original] = k
+	}
+	remap := func(indexes []int) []int {
+		if indexes == nil {
+			return nil
+		}
+		remapped := make([]int, len(indexes))
+		for i, index := range indexes {
+			remapped[i] = moved[index]
+		}
+		return remapped
+	}
+
+	question.Options = options
+	if at, exists := moved[question.CorrectIndex]; exists {
+		question.CorrectIndex = at
+	} else {
+		question.CorrectIndex = -1
+	}
+	question.CorrectIndexes = remap(question.CorrectIndexes)
+	question.CorrectOrder = remap(question.CorrectOrder)
+}
+
 // findCorrectIndex finds the index of the correct answer in the shuffled options.
 func findCorrectIndex(options []string, correctAnswer string) int {
 	for i, option := range options {
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/dedupe"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestBooleanQuestionsKeepTrueFalseOptions(t *testing.T) {
+	questions := FormatQuestionsWith([]models.APIQuestion{
+		{Type: "boolean", Question: "The sky is green.", CorrectAnswer: "False", IncorrectAnswers: []string{"True"}},
+		{Type: "multiple", Question: "2 + 2?", CorrectAnswer: "4", IncorrectAnswers: []string{"3", "5", "22"}},
+	}, rand.New(rand.NewSource(1)))
+
+	boolean, multiple := questions[0], questions[1]
+	if boolean.Type != models.QuestionBoolean || boolean.Options[0] != "True" || boolean.Options[1] != "False" || boolean.CorrectIndex != 1 {
+		t.Errorf("Unexpected true/false question: %+v", boolean)
+	}
+	if multiple.Type != models.QuestionMultiple || multiple.Options[multiple.CorrectIndex] != "4" {
+		t.Errorf("Unexpected multiple choice question: %+v", multiple)
+	}
+
+	bank := filepath.Join(t.TempDir(), "bank.json")
+	os.WriteFile(bank, []byte(`[
+		{"id": "1", "questionText": "Untyped", "options": ["a", "b", "c", "d"], "correctIndex": 2},
+		{"id": "2", "type": "boolean", "questionText": "Water is wet.", "correctIndex": 0}
+	]`), 0o644)
+	loaded, err := LoadQuestions(bank)
+	if err != nil {
+		t.Fatalf("Failed to load bank: %v", err)
+	}
+	if loaded[0].Type != models.QuestionMultiple || loaded[1].Type != models.QuestionBoolean || len(loaded[1].Options) != 2 {
+		t.Errorf("Bank questions were not normalized: %+v", loaded)
+	}
+
+	os.WriteFile(bank, []byte(`[{"id": "1", "type": "boolean", "questionText": "Bad", "correctIndex": 2}]`), 0o644)
+	if _, err := LoadQuestions(bank); err == nil {
+		t.Errorf("Expected an out-of-range correctIndex to be rejected")
+	}
+}
+
+func TestSeededQuestionsMoveCorrectAnswersWithOptions(t *testing.T) {
+	bank := []models.Question{
+		{ID: "1", Type: models.QuestionMultiSelect, QuestionText: "Even?", Options: []string{"2", "3", "4", "5", "6"}, CorrectIndexes: []int{0, 2, 4}},
+		{ID: "2", Type: models.QuestionOrdering, QuestionText: "Smallest first", Options: []string{"1", "2", "3", "4", "5"}, CorrectOrder: []int{0, 1, 2, 3, 4}},
+	}
+	for seed := int64(0); seed < 5; seed++ {
+		for _, question := range SeededQuestions(bank, len(bank), seed) {
+			switch question.Type {
+			case models.QuestionMultiSelect:
+				for _, index := range question.CorrectIndexes {
+					if option := question.Options[index]; option != "2" && option != "4" && option != "6" {
+						t.Errorf("Seed %d: correct option %q is not even", seed, option)
+					}
+				}
+			case models.QuestionOrdering:
+				for i, index := range question.CorrectOrder {
+					if want := string(rune('1' + i)); question.Options[index] != want {
+						t.Errorf("Seed %d: position %d holds %q; want %q", seed, i, question.Options[index], want)
+					}
+				}
+			}
+		}
+	}
+	if bank[1].Options[0] != "1" || bank[1].CorrectOrder[0] != 0 {
+		t.Errorf("Shuffling modified the bank: %+v", bank[1])
+	}
+}
+
+// countingProvider serves numbered questions and counts the questions it was asked for.
+// Once dry, it has none left to serve.
+type countingProvider struct {
+	served int
+	dry    bool
+}
+
+func (p *countingProvider) FetchQuestions(query Query) ([]models.Question, error) {
+	if p.dry {
+		return nil, nil
+	}
+	questions := make([]models.Question, query.Amount)
+	for i := range questions {
+		p.served++
+		questions[i] = models.Question{
+			QuestionText: fmt.Sprintf("Question number %d", p.served),
+			Options:      []string{fmt.Sprintf("Answer %d", p.served), "None"},
+		}
+	}
+	return questions, nil
+}
+
+func TestFetchedQuestionsLeaveOutExcludedOnesWhenOthersSuffice(t *testing.T) {
+	remote := &countingProvider{}
+	fetched, _ := remote.FetchQuestions(Query{Amount: 5})
+	exclude := map[string]bool{dedupe.Key(fetched[0]): true, dedupe.Key(fetched[2]): true}
+
+	picked := pickFetched(fetched, Query{Amount: 3, Exclude: exclude})
+	if len(picked) != 3 || picked[0].QuestionText != "Question number 2" || picked[2].QuestionText != "Question number 5" {
+		t.Errorf("Expected questions 2, 4 and 5; got %+v", picked)
+	}
+	picked = pickFetched(fetched, Query{Amount: 4, Exclude: exclude})
+	if len(picked) != 4 || picked[3].QuestionText != "Question number 1" {
+		t.Errorf("Expected an excluded question to make up the shortfall; got %+v", picked)
+	}
+}
+
+func TestPoolServesPrefetchedQuestionsAfterARestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pool.json")
+	remote := &countingProvider{}
+	pool := NewPool(remote, 10, 5, path)
+	pool.fillLowPools(context.Background())
+	if err := pool.save(); err != nil {
+		t.Fatalf("Failed to save pool: %v", err)
+	}
+
+	restarted := NewPool(remote, 10, 5, path)
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("Failed to load pool: %v", err)
+	}
+	seen := map[string]bool{dedupe.Key(models.Question{QuestionText: "Question number 1", Options: []string{"Answer 1", "None"}}): true}
+	questions, err := restarted.FetchQuestions(Query{Amount: 4, Type: models.QuestionMultiple, Exclude: seen})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remote.served != 10 || len(questions) != 4 || questions[0].QuestionText != "Question number 2" || questions[3].ID != "4" {
+		t.Errorf("Expected 4 pooled questions skipping the seen one, numbered from 1; got %+v after %d fetched", questions, remote.served)
+	}
+
+	// Once only the seen question is left, the rest are fetched rather than serving it.
+	questions, _ = restarted.FetchQuestions(Query{Amount: 6, Type: models.QuestionMultiple, Exclude: seen})
+	if remote.served != 11 || len(questions) != 6 || questions[0].QuestionText != "Question number 6" || questions[5].QuestionText != "Question number 11" {
+		t.Errorf("Expected 5 pooled questions and 1 fetched; got %+v after %d fetched", questions, remote.served)
+	}
+
+	// The seen question is served only when the remote has nothing else.
+	remote.dry = true
+	questions, _ = restarted.FetchQuestions(Query{Amount: 1, Type: models.QuestionMultiple, Exclude: seen})
+	if len(questions) != 1 || questions[0].QuestionText != "Question number 1" {
+		t.Errorf("Expected the seen question as a last resort; got %+v", questions)
+	}
+}
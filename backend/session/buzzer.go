@@ -0,0 +1,191 @@
+package session
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultBuzzWindow is how long a player who buzzed has to answer before the question reopens.
+const DefaultBuzzWindow = 5 * time.Second
+
+var (
+	ErrQuestionClosed = errors.New("question already won")
+	ErrBuzzLocked     = errors.New("another player holds the buzzer")
+	ErrLockedOut      = errors.New("player already missed this question")
+	ErrNotBuzzHolder  = errors.New("player does not hold the buzzer")
+)
+
+// Buzz records a single buzz in arrival order.
+type Buzz struct {
+	PlayerID   string    `json:"playerId"`
+	PlayerName string    `json:"playerName"`
+	BuzzedAt   time.Time `json:"buzzedAt"` // Server arrival time, the only clock trusted for ordering.
+	Accepted   bool      `json:"accepted"` // Whether the buzz won the buzzer.
+}
+
+// BuzzState tracks the buzzer for one question.
+type BuzzState struct {
+	QuestionID  string          `json:"questionId"`
+	HolderID    string          `json:"holderId,omitempty"`    // Player allowed to answer right now.
+	LockedUntil time.Time       `json:"lockedUntil,omitempty"` // When the holder's window closes.
+	LockedOut   map[string]bool `json:"lockedOut"`             // Players who answered wrong or ran out of time.
+	WinnerID    string          `json:"winnerId,omitempty"`    // Player who answered correctly, closing the question.
+	Skipped     bool            `json:"skipped,omitempty"`     // Whether the host closed the question unanswered.
+	Buzzes      []Buzz          `json:"buzzes"`                // Every buzz in server arrival order.
+	generation  int             // Incremented per accepted buzz so stale timers can be ignored.
+}
+
+// buzzState returns the buzzer for a question, creating it on first use. The caller must hold the lock.
+func (ps *PlayerSession) buzzState(questionID string) *BuzzState {
+	state, exists := ps.Buzzers[questionID]
+	if !exists {
+		state = &BuzzState{QuestionID: questionID, LockedOut: make(map[string]bool), Buzzes: []Buzz{}}
+		ps.Buzzers[questionID] = state
+	}
+	return state
+}
+
+// Buzz records a player's buzz for a question, ordered by server arrival time. The first
+// player to buzz on an open question holds the buzzer for the session's buzz window and
+// locks everyone else out; if they do not answer in time, the question reopens.
+func (ps *PlayerSession) Buzz(playerID, questionID string) (Buzz, error) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	player, exists := ps.Players[playerID]
+	if !exists {
+		return Buzz{}, ErrPlayerNotFound
+	}
+
+	now := time.Now()
+	state := ps.buzzState(questionID)
+	buzz := Buzz{PlayerID: playerID, PlayerName: player.Name, BuzzedAt: now}
+
+	switch {
+	case state.WinnerID != "" || state.Skipped:
+		return buzz, ErrQuestionClosed
+	case state.LockedOut[playerID]:
+		return buzz, ErrLockedOut
+	case state.HolderID != "" && now.Before(state.LockedUntil):
+		state.Buzzes = append(state.Buzzes, buzz)
+		return buzz, ErrBuzzLocked
+	}
+
+	buzz.Accepted = true
+	state.Buzzes = append(state.Buzzes, buzz)
+	state.HolderID = playerID
+	state.LockedUntil = now.Add(ps.BuzzWindow)
+	state.generation++
+
+	generation := state.generation
+	time.AfterFunc(ps.BuzzWindow, func() {
+		ps.expireBuzz(questionID, generation)
+	})
+	return buzz, nil
+}
+
+// ResolveBuzz settles the holder's answer. A correct answer closes the question; a wrong one
+// locks the player out and reopens the question for everyone else.
+func (ps *PlayerSession) ResolveBuzz(playerID, questionID string, correct bool) error {
+	ps.Lock()
+	defer ps.Unlock()
+
+	state := ps.buzzState(questionID)
+	if state.WinnerID != "" || state.Skipped {
+		return ErrQuestionClosed
+	}
+	if state.HolderID != playerID || time.Now().After(state.LockedUntil) {
+		return ErrNotBuzzHolder
+	}
+
+	if correct {
+		state.WinnerID = playerID
+	} else {
+		state.LockedOut[playerID] = true
+	}
+	state.HolderID = ""
+	state.LockedUntil = time.Time{}
+	return nil
+}
+
+// skipBuzzerQuestion closes the first open question without a winner, clearing its holder
+// and lockouts, so players move on to the next one.
+func (ps *PlayerSession) skipBuzzerQuestion() error {
+	ps.Lock()
+	reached := ps.reached()
+	if !ps.started() || ps.Phase == PhaseComplete || reached == 0 || ps.buzzerClosed(ps.Questions[reached-1].ID) {
+		ps.Unlock()
+		return ErrNothingToSkip
+	}
+	questionID := ps.Questions[reached-1].ID
+	state := ps.buzzState(questionID)
+	state.Skipped = true
+	state.HolderID = ""
+	state.LockedUntil = time.Time{}
+	state.LockedOut = make(map[string]bool)
+	state.generation++ // The holder's timer no longer applies.
+	ps.Unlock()
+
+	ps.Broadcast(map[string]interface{}{"type": "questionSkipped", "questionId": questionID})
+	ps.AnnounceFinishedRounds()
+	return nil
+}
+
+// BuzzerState returns a copy of the buzzer for a question.
+func (ps *PlayerSession) BuzzerState(questionID string) BuzzState {
+	ps.Lock()
+	defer ps.Unlock()
+
+	state := *ps.buzzState(questionID)
+	state.LockedOut = make(map[string]bool, len(ps.Buzzers[questionID].LockedOut))
+	for playerID := range ps.Buzzers[questionID].LockedOut {
+		state.LockedOut[playerID] = true
+	}
+	state.Buzzes = append([]Buzz{}, state.Buzzes...)
+	return state
+}
+
+// expireBuzz reopens a question whose holder ran out of time, treating the silence as a wrong answer.
+func (ps *PlayerSession) expireBuzz(questionID string, generation int) {
+	ps.Lock()
+	state := ps.buzzState(questionID)
+	expired := state.generation == generation && state.HolderID != "" && state.WinnerID == ""
+	if expired {
+		state.LockedOut[state.HolderID] = true
+		state.HolderID = ""
+		state.LockedUntil = time.Time{}
+	}
+	ps.Unlock()
+
+	if expired {
+		ps.BroadcastBuzzReopened(questionID, "timeout")
+		ps.AnnounceFinishedRounds()
+	}
+}
+
+// BroadcastBuzz announces who won the buzzer and until when they may answer.
+func (ps *PlayerSession) BroadcastBuzz(questionID string, buzz Buzz) {
+	ps.Broadcast(map[string]interface{}{
+		"type":        "buzz",
+		"questionId":  questionID,
+		"playerId":    buzz.PlayerID,
+		"playerName":  buzz.PlayerName,
+		"buzzedAt":    buzz.BuzzedAt,
+		"lockedUntil": buzz.BuzzedAt.Add(ps.BuzzWindow),
+	})
+}
+
+// BroadcastBuzzReopened announces that a question is open for buzzing again.
+func (ps *PlayerSession) BroadcastBuzzReopened(questionID, reason string) {
+	state := ps.BuzzerState(questionID)
+	lockedOut := make([]string, 0, len(state.LockedOut))
+	for playerID := range state.LockedOut {
+		lockedOut = append(lockedOut, playerID)
+	}
+	ps.Broadcast(map[string]interface{}{
+		"type":       "buzzReopened",
+		"questionId": questionID,
+		"reason":     reason,
+		"lockedOut":  lockedOut,
+	})
+}
@@ -0,0 +1,110 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestBuzzerLocksOutUntilWrongAnswerOrTimeout(t *testing.T) {
+	ps := newTestSession(t)
+	ps.Mode = ModeBuzzer
+	ps.BuzzWindow = 30 * time.Millisecond
+	first, second, third := ps.AddPlayer(), ps.AddPlayer(), ps.AddPlayer()
+
+	if _, err := ps.Buzz(first.ID, "1"); err != nil {
+		t.Fatalf("First buzz should be accepted: %v", err)
+	}
+	if _, err := ps.Buzz(second.ID, "1"); err != ErrBuzzLocked {
+		t.Fatalf("Second buzz should be locked out; got %v", err)
+	}
+
+	// A wrong answer reopens the question, but not for the player who missed it.
+	if err := ps.ResolveBuzz(first.ID, "1", false); err != nil {
+		t.Fatalf("Holder should be able to answer: %v", err)
+	}
+	if _, err := ps.Buzz(first.ID, "1"); err != ErrLockedOut {
+		t.Errorf("Player who answered wrong should stay locked out; got %v", err)
+	}
+	if _, err := ps.Buzz(second.ID, "1"); err != nil {
+		t.Fatalf("Reopened question should accept a new buzz: %v", err)
+	}
+
+	// Running out of time counts as a miss and reopens the question.
+	time.Sleep(60 * time.Millisecond)
+	if err := ps.ResolveBuzz(second.ID, "1", true); err != ErrNotBuzzHolder {
+		t.Errorf("Answer after the window should be refused; got %v", err)
+	}
+	if _, err := ps.Buzz(third.ID, "1"); err != nil {
+		t.Fatalf("Question should reopen after a timeout: %v", err)
+	}
+	if err := ps.ResolveBuzz(third.ID, "1", true); err != nil {
+		t.Fatalf("Holder should be able to answer: %v", err)
+	}
+
+	state := ps.BuzzerState("1")
+	if state.WinnerID != third.ID || len(state.Buzzes) != 4 {
+		t.Errorf("Expected %s to win after 4 buzzes; got %+v", third.ID, state)
+	}
+}
+
+func TestBuzzerQuestionsAreShownOnceEarlierOnesClose(t *testing.T) {
+	ps := newTestSession(t)
+	ps.Mode = ModeBuzzer
+	ps.Phase = PhaseInProgress
+	ps.Questions = []models.Question{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+	first, second := ps.AddPlayer(), ps.AddPlayer()
+
+	if !ps.Reached("1") || ps.Reached("2") {
+		t.Fatalf("Only the first question should be shown before any is closed")
+	}
+	ps.Buzz(first.ID, "1")
+	ps.ResolveBuzz(first.ID, "1", true)
+	if !ps.Reached("2") || ps.Reached("3") {
+		t.Fatalf("A won question should show the next one only")
+	}
+
+	// A question every player missed is closed too.
+	ps.Buzz(first.ID, "2")
+	ps.ResolveBuzz(first.ID, "2", false)
+	ps.Buzz(second.ID, "2")
+	ps.ResolveBuzz(second.ID, "2", false)
+	if !ps.Reached("3") {
+		t.Errorf("A question missed by everyone should show the next one")
+	}
+}
+
+func TestSkippingABuzzerQuestionClosesItAndShowsTheNext(t *testing.T) {
+	ps := newTestSession(t)
+	ps.Mode = ModeBuzzer
+	ps.Phase = PhaseInProgress
+	ps.Questions = []models.Question{{ID: "1"}, {ID: "2"}}
+	first, second := ps.AddPlayer(), ps.AddPlayer()
+
+	ps.Buzz(first.ID, "1")
+	ps.ResolveBuzz(first.ID, "1", false)
+	ps.Buzz(second.ID, "1")
+	if err := ps.SkipCurrentQuestion(); err != nil {
+		t.Fatalf("SkipCurrentQuestion failed: %v", err)
+	}
+	if state := ps.BuzzerState("1"); !state.Skipped || state.HolderID != "" || len(state.LockedOut) != 0 {
+		t.Errorf("Expected the skip to clear the holder and lockouts; got %+v", state)
+	}
+	if err := ps.ResolveBuzz(second.ID, "1", true); err != ErrQuestionClosed {
+		t.Errorf("The holder of a skipped question should not score; got %v", err)
+	}
+	if !ps.Reached("2") {
+		t.Errorf("Skipping should show the next question")
+	}
+
+	ps.SkipCurrentQuestion()
+	if err := ps.SkipCurrentQuestion(); err != ErrNothingToSkip {
+		t.Errorf("Expected nothing left to skip; got %v", err)
+	}
+
+	ps.Mode = ModeClassic
+	if err := ps.SkipCurrentQuestion(); err != ErrSkipUnsupported {
+		t.Errorf("Expected classic games to refuse skipping; got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package session
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPaused          = errors.New("game is paused")
+	ErrAlreadyPaused   = errors.New("game is already paused")
+	ErrNotPaused       = errors.New("game is not paused")
+	ErrNotRunning      = errors.New("game is not running")
+	ErrNothingToSkip   = errors.New("no question to skip")
+	ErrSkipUnsupported = errors.New("classic players answer at their own pace, so there is no shared question to skip")
+)
+
+// phaseTimer times the running countdown, question or reveal. Pausing the game stops it and
+// resuming pushes its end back by the time spent paused.
+type phaseTimer struct {
+	ends    time.Time     // When the phase ends, as of the last resume.
+	changed chan struct{} // Signalled when the game is paused or resumed.
+	skipped chan struct{} // Closed when the host skips the phase.
+	skip    bool          // Whether skipped has been closed.
+}
+
+// deadline returns when a phase of the given length starting now ends. While the game is
+// paused the phase has not started yet, so it is timed from the pause. The caller must hold the lock.
+func (ps *PlayerSession) deadline(length time.Duration) time.Time {
+	if ps.Paused {
+		return ps.pausedAt.Add(length)
+	}
+	return time.Now().Add(length)
+}
+
+// runTimer waits out a phase of the given length, honouring pause, resume and skip. It
+// returns early when done is closed, as when every player has answered, reports whether
+// the host skipped the phase, and returns the context's error if the session is closed.
+func (ps *PlayerSession) runTimer(length time.Duration, done <-chan struct{}) (bool, error) {
+	ps.Lock()
+	timer := &phaseTimer{
+		ends:    ps.deadline(length),
+		changed: make(chan struct{}, 1),
+		skipped: make(chan struct{}),
+	}
+	ps.timer = timer
+	ps.Unlock()
+
+	defer func() {
+		ps.Lock()
+		if ps.timer == timer {
+			ps.timer = nil
+		}
+		ps.Unlock()
+	}()
+
+	for {
+		ps.Lock()
+		paused, wait := ps.Paused, time.Until(timer.ends)
+		ps.Unlock()
+
+		// While paused, only a resume, a skip or the session closing can end the wait.
+		var clock *time.Timer
+		var expired <-chan time.Time
+		if !paused {
+			clock = time.NewTimer(wait)
+			expired = clock.C
+		}
+
+		select {
+		case <-ps.ctx.Done():
+			return false, ps.ctx.Err()
+		case <-done:
+			return false, nil
+		case <-timer.skipped:
+			return true, nil
+		case <-expired:
+			return false, nil
+		case <-timer.changed:
+			if clock != nil {
+				clock.Stop()
+			}
+		}
+	}
+}
+
+// awaitResume returns once the game is not paused, so a phase the host skipped while paused
+// does not let the game move on. It returns the context's error if the session is closed.
+func (ps *PlayerSession) awaitResume() error {
+	_, err := ps.runTimer(0, nil) // A paused timer only ends on resume.
+	return err
+}
+
+// remaining returns the time left in the running countdown or question, frozen while the
+// game is paused. The caller must hold the lock.
+func (ps *PlayerSession) remaining() time.Duration {
+	var ends time.Time
+	switch ps.Phase {
+	case PhaseCountdown:
+		ends = ps.CountdownEnds
+	case PhaseQuestion:
+		ends = ps.QuestionEnds
+	}
+	if ends.IsZero() {
+		return 0
+	}
+
+	now := time.Now()
+	if ps.Paused {
+		now = ps.pausedAt
+	}
+	if left := ends.Sub(now); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// IsPaused reports whether the host has paused the game.
+func (ps *PlayerSession) IsPaused() bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	return ps.Paused
+}
+
+// Pause freezes the game: the running countdown or question stops with its time left, and
+// answers are refused until the game resumes.
+func (ps *PlayerSession) Pause() error {
+	ps.Lock()
+	if ps.Phase == PhaseWaiting || ps.Phase == PhaseComplete {
+		ps.Unlock()
+		return ErrNotRunning
+	}
+	if ps.Paused {
+		ps.Unlock()
+		return ErrAlreadyPaused
+	}
+	ps.Paused = true
+	ps.pausedAt = time.Now()
+	ps.notifyTimer()
+	remaining := ps.remaining()
+	ps.Unlock()
+
+	ps.Broadcast(map[string]interface{}{"type": "paused", "remainingTime": int(remaining.Round(time.Second) / time.Second)})
+	return nil
+}
+
+// Resume restarts a paused game with the time that was left when it was paused.
+func (ps *PlayerSession) Resume() error {
+	ps.Lock()
+	if !ps.Paused {
+		ps.Unlock()
+		return ErrNotPaused
+	}
+	pausedFor := time.Since(ps.pausedAt)
+	ps.Paused = false
+	if !ps.CountdownEnds.IsZero() {
+		ps.CountdownEnds = ps.CountdownEnds.Add(pausedFor)
+	}
+	if !ps.QuestionEnds.IsZero() {
+		ps.QuestionEnds = ps.QuestionEnds.Add(pausedFor)
+	}
+	if ps.timer != nil {
+		ps.timer.ends = ps.timer.ends.Add(pausedFor)
+	}
+	ps.notifyTimer()
+	remaining := ps.remaining()
+	ps.Unlock()
+
+	ps.Broadcast(map[string]interface{}{"type": "resumed", "remainingTime": int(remaining.Round(time.Second) / time.Second)})
+	return nil
+}
+
+// SkipCurrentQuestion ends the current question without scoring it: in elimination the
+// question the server is asking, and in buzzer mode the first question still open.
+// Classic games are refused with ErrSkipUnsupported.
+func (ps *PlayerSession) SkipCurrentQuestion() error {
+	switch ps.Mode {
+	case ModeClassic:
+		return ErrSkipUnsupported
+	case ModeBuzzer:
+		return ps.skipBuzzerQuestion()
+	}
+
+	ps.Lock()
+	defer ps.Unlock()
+
+	if ps.Phase != PhaseQuestion || ps.timer == nil || ps.timer.skip {
+		return ErrNothingToSkip
+	}
+	ps.timer.skip = true
+	close(ps.timer.skipped)
+	return nil
+}
+
+// notifyTimer wakes the running timer to pick up a pause or resume. The caller must hold the lock.
+func (ps *PlayerSession) notifyTimer() {
+	if ps.timer == nil {
+		return
+	}
+	select {
+	case ps.timer.changed <- struct{}{}:
+	default: // A wake-up is already pending.
+	}
+}
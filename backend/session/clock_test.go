@@ -0,0 +1,73 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestPauseFreezesTimeAndSkipDoesNotScore(t *testing.T) {
+	ps := newTestSession(t)
+	ps.Mode = ModeElimination
+	ps.TimeLimit = 200 * time.Millisecond
+	ps.RevealDuration = 0
+	ps.Questions = []models.Question{{ID: "1"}, {ID: "2"}}
+	first, second := ps.AddPlayer(), ps.AddPlayer()
+
+	done := make(chan struct{})
+	go func() {
+		ps.RunElimination()
+		close(done)
+	}()
+
+	for i := 0; i < 100 && ps.Snapshot("").Phase != PhaseQuestion; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := ps.Pause(); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if err := ps.SubmitPacedAnswer(first.ID, "1", true); err != ErrPaused {
+		t.Errorf("Expected answers to be refused while paused; got %v", err)
+	}
+
+	// Well past the time limit, the paused question must still be open.
+	time.Sleep(300 * time.Millisecond)
+	if ps.Snapshot("").Phase != PhaseQuestion {
+		t.Fatalf("Question should not time out while paused")
+	}
+	if err := ps.SkipCurrentQuestion(); err != nil {
+		t.Fatalf("Skip failed: %v", err)
+	}
+
+	// The next question waits for the host to resume.
+	time.Sleep(50 * time.Millisecond)
+	if snapshot := ps.Snapshot(""); snapshot.Phase == PhaseQuestion {
+		t.Fatalf("The next question should not be asked while paused")
+	}
+	if err := ps.Resume(); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	// Question 2 decides the game: the skipped question eliminated nobody.
+	for _, submission := range []struct {
+		playerID string
+		correct  bool
+	}{{first.ID, true}, {second.ID, false}} {
+		for i := 0; i < 100; i++ {
+			if err := ps.SubmitPacedAnswer(submission.playerID, "2", submission.correct); err != ErrNotCurrentQuestion {
+				break
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Elimination game did not finish")
+	}
+	if first.Placement != 1 || second.Placement != 2 {
+		t.Errorf("Unexpected placements: first=%d second=%d", first.Placement, second.Placement)
+	}
+}
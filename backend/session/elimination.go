@@ -0,0 +1,154 @@
+package session
+
+import (
+	"log"
+	"sort"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// RunElimination plays the session as last player standing. Each question is pushed to every
+// remaining player; a wrong or missing answer eliminates the player, who stays connected as a
+// spectator. If every remaining player misses the same question, nobody is eliminated. The
+// game ends when one player remains or the questions run out, and placements follow
+// elimination order. Questions the host skips do not count. In games played in rounds, the
+// scoreboard is shown for an intermission between rounds. It returns an error if the
+// session closes before the game ends.
+func (ps *PlayerSession) RunElimination() error {
+	active := ps.activeContestants()
+	contestants := len(active)
+
+	for index := range ps.Questions {
+		if len(active) == 0 || (contestants > 1 && len(active) <= 1) {
+			break
+		}
+
+		result, err := ps.askQuestion(index, active)
+		if err != nil {
+			return err
+		}
+		if !result.Skipped {
+			for _, playerID := range result.Correct {
+				ps.UpdatePlayerScore(playerID, result.QuestionID, ps.PointsFor(result.QuestionID))
+			}
+
+			knockedOut := append(result.Wrong, result.Missing...)
+			if len(knockedOut) < len(active) {
+				ps.eliminate(knockedOut, len(active)-len(knockedOut)+1)
+			}
+
+			if _, err := ps.runTimer(ps.RevealDuration, nil); err != nil {
+				return err
+			}
+		}
+		active = ps.activeContestants()
+		if err := ps.intermission(result.QuestionID, len(active)); err != nil {
+			return err
+		}
+	}
+
+	ps.placeSurvivors()
+	ps.Complete()
+	log.Printf("Elimination finished for session %s", ps.ID)
+	ps.Broadcast(map[string]interface{}{"type": "sessionComplete"})
+	return nil
+}
+
+// activeContestants returns the players still in the game. Everyone present for the first
+// question is a contestant; players who join later are marked eliminated without a
+// placement and watch as spectators.
+func (ps *PlayerSession) activeContestants() map[string]bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	if ps.contestants == nil {
+		ps.contestants = make(map[string]bool)
+		for playerID := range ps.Players {
+			ps.contestants[playerID] = true
+		}
+	}
+
+	active := make(map[string]bool)
+	for _, player := range ps.Players {
+		if !ps.contestants[player.ID] && !player.Eliminated {
+			player.Eliminated = true
+			player.Finished = true
+		}
+		if !player.Eliminated {
+			active[player.ID] = true
+		}
+	}
+	return active
+}
+
+// eliminate knocks players out, giving them all the same placement, and announces it.
+func (ps *PlayerSession) eliminate(playerIDs []string, placement int) {
+	if len(playerIDs) == 0 {
+		return
+	}
+
+	ps.Lock()
+	eliminated := make([]map[string]interface{}, 0, len(playerIDs))
+	for _, playerID := range playerIDs {
+		if player, exists := ps.Players[playerID]; exists {
+			player.Eliminated = true
+			player.Finished = true
+			player.Placement = placement
+			eliminated = append(eliminated, map[string]interface{}{"playerId": player.ID, "playerName": player.Name})
+		}
+	}
+	remaining := 0
+	for _, player := range ps.Players {
+		if !player.Eliminated {
+			remaining++
+		}
+	}
+	ps.Unlock()
+
+	ps.Broadcast(map[string]interface{}{
+		"type":      "eliminated",
+		"players":   eliminated,
+		"placement": placement,
+		"remaining": remaining,
+	})
+}
+
+// placeSurvivors ranks the players left standing by score; survivors level on score share a placement.
+func (ps *PlayerSession) placeSurvivors() {
+	ps.Lock()
+	defer ps.Unlock()
+
+	var survivors []*models.Player
+	for _, player := range ps.Players {
+		if !player.Eliminated && ps.contestants[player.ID] {
+			survivors = append(survivors, player)
+		}
+	}
+	sort.Slice(survivors, func(i, j int) bool { return survivors[i].Score > survivors[j].Score })
+
+	for i, player := range survivors {
+		if i > 0 && survivors[i-1].Score == player.Score {
+			player.Placement = survivors[i-1].Placement
+		} else {
+			player.Placement = i + 1
+		}
+		player.Finished = true
+	}
+}
+
+// intermission shows the scoreboard when a question ends a round, and waits before the next
+// round starts. There is no intermission once the game is decided.
+func (ps *PlayerSession) intermission(questionID string, active int) error {
+	ps.Lock()
+	round, ends := ps.endsRound(questionID)
+	contestants := len(ps.contestants)
+	pause := ps.Intermission
+	ps.Unlock()
+
+	if !ends || active == 0 || (contestants > 1 && active <= 1) {
+		return nil
+	}
+	ps.broadcastIntermission(round, pause)
+	_, err := ps.runTimer(pause, nil)
+	return err
+}
@@ -0,0 +1,63 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestEliminationPlacementsFollowEliminationOrder(t *testing.T) {
+	ps := newTestSession(t)
+	ps.Mode = ModeElimination
+	ps.TimeLimit = 200 * time.Millisecond
+	ps.RevealDuration = 0
+	ps.Questions = []models.Question{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+	first, second, third := ps.AddPlayer(), ps.AddPlayer(), ps.AddPlayer()
+
+	done := make(chan struct{})
+	go func() {
+		ps.RunElimination()
+		close(done)
+	}()
+
+	// answer waits for the question to open, then submits.
+	answer := func(playerID, questionID string, correct bool) {
+		for i := 0; i < 100; i++ {
+			if err := ps.SubmitPacedAnswer(playerID, questionID, correct); err != ErrNotCurrentQuestion {
+				if err != nil {
+					t.Errorf("Answer from %s to %s failed: %v", playerID, questionID, err)
+				}
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Errorf("Question %s never opened", questionID)
+	}
+
+	// Question 1: third misses and is out.
+	answer(first.ID, "1", true)
+	answer(second.ID, "1", true)
+	// Question 2: both survivors miss, so nobody is eliminated.
+	answer(first.ID, "2", false)
+	answer(second.ID, "2", false)
+	// Question 3: second answers wrong and the game ends with first standing.
+	answer(first.ID, "3", true)
+	answer(second.ID, "3", false)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Elimination game did not finish")
+	}
+
+	if third.Placement != 3 || second.Placement != 2 || first.Placement != 1 {
+		t.Errorf("Unexpected placements: first=%d second=%d third=%d", first.Placement, second.Placement, third.Placement)
+	}
+	if !third.Eliminated || first.Eliminated {
+		t.Errorf("Only the winner should be left standing")
+	}
+	if ps.Phase != PhaseComplete {
+		t.Errorf("Expected the session to be complete; got %s", ps.Phase)
+	}
+}
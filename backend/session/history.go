@@ -0,0 +1,43 @@
+package session
+
+import (
+	"github.com/gclluch/TriviaApp-ReactGo/dedupe"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// markAnswered records that a player has submitted an answer for a question, in the session
+// and, for players known by a user ID, in the history of questions they have answered. The
+// caller must hold the lock.
+func (ps *PlayerSession) markAnswered(player *models.Player, questionID string) {
+	player.Answered[questionID] = true
+	ps.remember(player.UserID, questionID)
+}
+
+// remember queues a question for the history of the user who answered it, if they are known.
+// The history is written in the background, so the lock is not held while the log file is.
+// The caller must hold the lock.
+func (ps *PlayerSession) remember(userID, questionID string) {
+	if ps.History == nil || userID == "" {
+		return
+	}
+	if question, exists := ps.question(questionID); exists {
+		ps.History.Queue(userID, dedupe.Key(question))
+	}
+}
+
+// UserIDs returns the user IDs of the players known by one, and of the single player if known.
+func (ps *PlayerSession) UserIDs() []string {
+	ps.Lock()
+	defer ps.Unlock()
+
+	var userIDs []string
+	if ps.SoloUserID != "" {
+		userIDs = append(userIDs, ps.SoloUserID)
+	}
+	for _, player := range ps.Players {
+		if player.UserID != "" {
+			userIDs = append(userIDs, player.UserID)
+		}
+	}
+	return userIDs
+}
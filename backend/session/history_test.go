@@ -0,0 +1,29 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/dedupe"
+	"github.com/gclluch/TriviaApp-ReactGo/history"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestSoloAnswersGoInThePlayersHistory(t *testing.T) {
+	answered, err := history.Open(filepath.Join(t.TempDir(), "answered.jsonl"), 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	ps := newTestSession(t)
+	ps.History = answered
+	ps.SoloUserID = "ann"
+	ps.Questions = []models.Question{{ID: "1", QuestionText: "Solo?", Options: []string{"Yes", "No"}}}
+
+	if err := ps.SubmitSoloAnswer("1", 1); err != nil {
+		t.Fatalf("SubmitSoloAnswer failed: %v", err)
+	}
+	answered.Flush()
+	if seen := answered.Seen("ann"); !seen[dedupe.Key(ps.Questions[0])] {
+		t.Errorf("Expected the single player's answer to be remembered; got %v", seen)
+	}
+}
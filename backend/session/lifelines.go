@@ -0,0 +1,241 @@
+package session
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// Lifeline names a single-player aid that can be spent on a question.
+type Lifeline string
+
+const (
+	LifelineFiftyFifty Lifeline = "fiftyFifty"     // Rules out two wrong options.
+	LifelineSkip       Lifeline = "skip"           // Replaces the question with a fresh one.
+	LifelineAudience   Lifeline = "askTheAudience" // Shows how earlier players answered the question.
+)
+
+// MaxLifelineUses is the most uses of each lifeline a game may start with. Each skip fetches
+// a replacement question, so the inventory is kept small.
+const MaxLifelineUses = 3
+
+var (
+	ErrLifelinesUnavailable = errors.New("lifelines are only available in single-player classic games")
+	ErrNoLifelineLeft       = errors.New("no uses of this lifeline left")
+	ErrLifelineUsed         = errors.New("lifeline already used on this question")
+	ErrLifelineNotUsable    = errors.New("lifeline cannot be used on this question")
+	ErrQuestionNotFound     = errors.New("question not found")
+	ErrQuestionAnswered     = errors.New("question already answered or skipped")
+)
+
+// LifelineUse records a lifeline spent on a question, for the score breakdown.
+type LifelineUse struct {
+	Lifeline      Lifeline  `json:"lifeline"`
+	QuestionID    string    `json:"questionId"`
+	Removed       []int     `json:"removed,omitempty"`       // Options ruled out by 50/50.
+	ReplacementID string    `json:"replacementId,omitempty"` // Question swapped in by skip.
+	UsedAt        time.Time `json:"usedAt"`
+}
+
+// QuestionScore is one line of a score breakdown.
+type QuestionScore struct {
+	QuestionID string     `json:"questionId"`
+	Answered   bool       `json:"answered"`
+	Correct    bool       `json:"correct"`
+	Skipped    bool       `json:"skipped"`
+	Points     int        `json:"points"`
+	Lifelines  []Lifeline `json:"lifelines"` // Lifelines used on the question, in order.
+}
+
+// ScoreBreakdown itemizes how a single-player score was reached.
+type ScoreBreakdown struct {
+	Questions []QuestionScore  `json:"questions"`
+	Lifelines []LifelineUse    `json:"lifelines"`
+	Remaining map[Lifeline]int `json:"remaining"` // Lifelines left unused.
+	Total     int              `json:"total"`
+}
+
+// ValidLifeline reports whether lifeline is a supported lifeline.
+func ValidLifeline(lifeline Lifeline) bool {
+	switch lifeline {
+	case LifelineFiftyFifty, LifelineSkip, LifelineAudience:
+		return true
+	}
+	return false
+}
+
+// ConfigureLifelines sets the session's lifeline inventory.
+func (ps *PlayerSession) ConfigureLifelines(inventory map[Lifeline]int) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	ps.Lifelines = make(map[Lifeline]int, len(inventory))
+	for lifeline, count := range inventory {
+		ps.Lifelines[lifeline] = count
+	}
+}
+
+// RemainingLifelines returns a copy of the session's unused lifelines.
+func (ps *PlayerSession) RemainingLifelines() map[Lifeline]int {
+	ps.Lock()
+	defer ps.Unlock()
+
+	return ps.remainingLifelines()
+}
+
+// remainingLifelines is RemainingLifelines for callers holding the lock.
+func (ps *PlayerSession) remainingLifelines() map[Lifeline]int {
+	remaining := make(map[Lifeline]int, len(ps.Lifelines))
+	for lifeline, count := range ps.Lifelines {
+		remaining[lifeline] = count
+	}
+	return remaining
+}
+
+// CheckLifeline reports whether a lifeline could be used on a question right now, without
+// spending it. Callers use it to avoid expensive work for a request that will be refused.
+func (ps *PlayerSession) CheckLifeline(questionID string, lifeline Lifeline) error {
+	ps.Lock()
+	defer ps.Unlock()
+
+	_, err := ps.checkLifeline(questionID, lifeline)
+	return err
+}
+
+// checkLifeline validates a lifeline use and returns the question. The caller must hold the lock.
+func (ps *PlayerSession) checkLifeline(questionID string, lifeline Lifeline) (models.Question, error) {
+	if !ps.solo() {
+		return models.Question{}, ErrLifelinesUnavailable
+	}
+
+	var question models.Question
+	found := false
+	for _, candidate := range ps.Questions {
+		if candidate.ID == questionID {
+			question, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		return question, ErrQuestionNotFound
+	}
+	if ps.questionClosed(questionID) {
+		return question, ErrQuestionAnswered
+	}
+	for _, use := range ps.LifelineUses {
+		if use.QuestionID == questionID && use.Lifeline == lifeline {
+			return question, ErrLifelineUsed
+		}
+	}
+	if ps.Lifelines[lifeline] <= 0 {
+		return question, ErrNoLifelineLeft
+	}
+	return question, nil
+}
+
+// spendLifeline consumes a lifeline and records its use. The caller must hold the lock.
+func (ps *PlayerSession) spendLifeline(use LifelineUse) {
+	use.UsedAt = time.Now()
+	ps.Lifelines[use.Lifeline]--
+	ps.LifelineUses = append(ps.LifelineUses, use)
+}
+
+// FiftyFifty spends a 50/50 on a question and returns the indexes of two wrong options,
+// chosen at random, that the player can rule out.
+func (ps *PlayerSession) FiftyFifty(questionID string) ([]int, error) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	question, err := ps.checkLifeline(questionID, LifelineFiftyFifty)
+	if err != nil {
+		return nil, err
+	}
+	if !question.SingleAnswer() {
+		return nil, ErrLifelineNotUsable
+	}
+
+	var wrong []int
+	for i := range question.Options {
+		if i != question.CorrectIndex {
+			wrong = append(wrong, i)
+		}
+	}
+	// Ruling out two options only helps if at least one wrong option is left standing.
+	if len(wrong) < 3 {
+		return nil, ErrLifelineNotUsable
+	}
+	rand.Shuffle(len(wrong), func(i, j int) { wrong[i], wrong[j] = wrong[j], wrong[i] })
+	removed := []int{wrong[0], wrong[1]}
+	if removed[0] > removed[1] {
+		removed[0], removed[1] = removed[1], removed[0]
+	}
+
+	ps.spendLifeline(LifelineUse{Lifeline: LifelineFiftyFifty, QuestionID: questionID, Removed: removed})
+	return removed, nil
+}
+
+// SkipQuestion spends a skip, closing a question and appending the replacement to the
+// session under a new ID. The replacement is returned with its assigned ID.
+func (ps *PlayerSession) SkipQuestion(questionID string, replacement models.Question) (models.Question, error) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	if _, err := ps.checkLifeline(questionID, LifelineSkip); err != nil {
+		return models.Question{}, err
+	}
+
+	// Question IDs run from 1 to the number of questions, so the next one is unused.
+	replacement.ID = strconv.Itoa(len(ps.Questions) + 1)
+	ps.Questions = append(ps.Questions, replacement)
+	ps.addToRound(questionID, replacement.ID)
+	ps.spendLifeline(LifelineUse{Lifeline: LifelineSkip, QuestionID: questionID, ReplacementID: replacement.ID})
+	return replacement, nil
+}
+
+// AskTheAudience spends an ask-the-audience on a question and returns the question, so the
+// caller can look up how earlier players answered it.
+func (ps *PlayerSession) AskTheAudience(questionID string) (models.Question, error) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	question, err := ps.checkLifeline(questionID, LifelineAudience)
+	if err != nil {
+		return models.Question{}, err
+	}
+	// The audience only ever picked one option.
+	if !question.SingleAnswer() {
+		return models.Question{}, ErrLifelineNotUsable
+	}
+	ps.spendLifeline(LifelineUse{Lifeline: LifelineAudience, QuestionID: questionID})
+	return question, nil
+}
+
+// Breakdown itemizes the single-player score per question along with every lifeline used.
+func (ps *PlayerSession) Breakdown() ScoreBreakdown {
+	ps.Lock()
+	defer ps.Unlock()
+
+	breakdown := ScoreBreakdown{
+		Questions: make([]QuestionScore, 0, len(ps.Questions)),
+		Lifelines: append([]LifelineUse{}, ps.LifelineUses...),
+		Remaining: ps.remainingLifelines(),
+		Total:     ps.Score,
+	}
+	for _, question := range ps.Questions {
+		line := QuestionScore{QuestionID: question.ID, Lifelines: []Lifeline{}}
+		credit, answered := ps.soloCredit[question.ID]
+		line.Answered, line.Correct = answered, credit >= 1
+		line.Points = ps.soloPoints(question.ID)
+		for _, use := range ps.LifelineUses {
+			if use.QuestionID == question.ID {
+				line.Lifelines = append(line.Lifelines, use.Lifeline)
+				line.Skipped = line.Skipped || use.Lifeline == LifelineSkip
+			}
+		}
+		breakdown.Questions = append(breakdown.Questions, line)
+	}
+	return breakdown
+}
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestLifelinesAreValidatedAndItemized(t *testing.T) {
+	ps := newTestSession(t)
+	ps.Questions = []models.Question{
+		{ID: "1", Options: []string{"a", "b", "c", "d"}, CorrectIndex: 2},
+		{ID: "2", Options: []string{"True", "False"}, CorrectIndex: 0},
+	}
+	ps.ConfigureLifelines(map[Lifeline]int{LifelineFiftyFifty: 1, LifelineSkip: 1})
+
+	removed, err := ps.FiftyFifty("1")
+	if err != nil {
+		t.Fatalf("FiftyFifty failed: %v", err)
+	}
+	if len(removed) != 2 || removed[0] == 2 || removed[1] == 2 || removed[0] == removed[1] {
+		t.Errorf("50/50 should rule out two distinct wrong options; got %v", removed)
+	}
+	if _, err := ps.FiftyFifty("1"); err != ErrLifelineUsed {
+		t.Errorf("Expected ErrLifelineUsed on a second 50/50; got %v", err)
+	}
+	if _, err := ps.AskTheAudience("1"); err != ErrNoLifelineLeft {
+		t.Errorf("Expected ErrNoLifelineLeft for a lifeline not in the inventory; got %v", err)
+	}
+
+	replacement, err := ps.SkipQuestion("2", models.Question{ID: "1", Options: []string{"x", "y"}})
+	if err != nil {
+		t.Fatalf("SkipQuestion failed: %v", err)
+	}
+	if replacement.ID != "3" {
+		t.Errorf("Replacement should get a fresh ID; got %s", replacement.ID)
+	}
+	if err := ps.SubmitSoloAnswer("2", 1); err != ErrQuestionAnswered {
+		t.Errorf("A skipped question should not be answerable; got %v", err)
+	}
+
+	if err := ps.SubmitSoloAnswer("1", 1); err != nil {
+		t.Fatalf("SubmitSoloAnswer failed: %v", err)
+	}
+	if err := ps.SubmitSoloAnswer("1", 1); err != ErrQuestionAnswered {
+		t.Errorf("A question should only score once; got %v", err)
+	}
+
+	breakdown := ps.Breakdown()
+	if breakdown.Total != 10 || len(breakdown.Lifelines) != 2 || len(breakdown.Questions) != 3 {
+		t.Fatalf("Unexpected breakdown: %+v", breakdown)
+	}
+	if first := breakdown.Questions[0]; !first.Correct || first.Points != 10 || len(first.Lifelines) != 1 {
+		t.Errorf("Unexpected breakdown for question 1: %+v", first)
+	}
+	if !breakdown.Questions[1].Skipped {
+		t.Errorf("Question 2 should be itemized as skipped")
+	}
+
+	ps.AddPlayer()
+	if _, err := ps.FiftyFifty("3"); err != ErrLifelinesUnavailable {
+		t.Errorf("Lifelines should be unavailable in multiplayer sessions; got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package session
+
+import "github.com/gclluch/TriviaApp-ReactGo/models"
+
+// PublicQuestion returns the view of a question sent to players as it is asked, with URLs for
+// its media handed out now, so they expire a while after the question rather than after the game starts.
+func (ps *PlayerSession) PublicQuestion(question models.Question) models.PublicQuestion {
+	public := question.Public()
+	public.Media = ps.Media.Attach(question.Media)
+	return public
+}
+
+// PublicQuestions returns the questions players may see so far, without their answers or
+// media. Players who answer at their own pace fetch each question with ReachedQuestion as
+// they come to it, which is when its media URLs are handed out.
+func (ps *PlayerSession) PublicQuestions() []models.PublicQuestion {
+	ps.Lock()
+	defer ps.Unlock()
+
+	questions := make([]models.PublicQuestion, ps.reached())
+	for i, question := range ps.Questions[:len(questions)] {
+		questions[i] = question.Public()
+	}
+	return questions
+}
+
+// ReachedQuestion returns the view of a question players may see yet, with URLs for its
+// media handed out now.
+func (ps *PlayerSession) ReachedQuestion(questionID string) (models.PublicQuestion, bool) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	for _, question := range ps.Questions[:ps.reached()] {
+		if question.ID == questionID {
+			return ps.PublicQuestion(question), true
+		}
+	}
+	return models.PublicQuestion{}, false
+}
@@ -0,0 +1,117 @@
+package session
+
+import (
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/grading"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// playerGuesses maps player IDs to their guesses at one numeric question.
+type playerGuesses map[string]float64
+
+// SubmitGuess records a player's guess at a numeric question. Guesses are scored together
+// once every player has guessed; see RevealSettledGuesses.
+func (ps *PlayerSession) SubmitGuess(playerID, questionID string, guess float64) error {
+	ps.Lock()
+	defer ps.Unlock()
+
+	player, exists := ps.Players[playerID]
+	if !exists {
+		return ErrPlayerNotFound
+	}
+	if ps.revealedGuesses[questionID] {
+		return ErrQuestionAnswered
+	}
+	guesses, exists := ps.guesses[questionID]
+	if !exists {
+		guesses = make(playerGuesses)
+		ps.guesses[questionID] = guesses
+	}
+	if _, guessed := guesses[playerID]; guessed {
+		return ErrAlreadyAnswered
+	}
+	guesses[playerID] = guess
+	ps.markAnswered(player, questionID)
+	return nil
+}
+
+// RevealSettledGuesses scores every numeric question that all players have either guessed
+// or finished the game without guessing, and broadcasts each reveal with everyone's guesses.
+func (ps *PlayerSession) RevealSettledGuesses() {
+	ps.Lock()
+	var reveals []map[string]interface{}
+	for questionID, guesses := range ps.guesses {
+		if ps.revealedGuesses[questionID] || !ps.guessesSettled(guesses) {
+			continue
+		}
+		question, exists := ps.question(questionID)
+		if !exists {
+			continue
+		}
+		reveals = append(reveals, ps.scoreGuesses(question, guesses))
+	}
+	ps.Unlock()
+
+	for _, reveal := range reveals {
+		ps.Broadcast(reveal)
+	}
+	if len(reveals) > 0 {
+		ps.BroadcastHighScore()
+		if ps.TeamMode {
+			ps.BroadcastTeamScores()
+		}
+	}
+}
+
+// guessesSettled reports whether no player is still to guess. The caller must hold the lock.
+func (ps *PlayerSession) guessesSettled(guesses playerGuesses) bool {
+	for playerID, player := range ps.Players {
+		if _, guessed := guesses[playerID]; !guessed && !player.Finished {
+			return false
+		}
+	}
+	return true
+}
+
+// scoreGuesses awards the points for a numeric question and builds its reveal. The caller must hold the lock.
+func (ps *PlayerSession) scoreGuesses(question models.Question, guesses playerGuesses) map[string]interface{} {
+	ps.revealedGuesses[question.ID] = true
+	results := grading.ScoreGuesses(question.NumericValue(), guesses, ps.pointsFor(question.ID), ps.NumericScoring)
+
+	for i := range results {
+		player, exists := ps.Players[results[i].PlayerID]
+		if !exists {
+			continue
+		}
+		results[i].PlayerName = player.Name
+		if points := results[i].Points; points > 0 {
+			player.Score += points
+			player.Points[question.ID] += points
+			player.LastScoredAt = time.Now()
+		}
+		if results[i].Distance == 0 {
+			player.Correct++ // Only a guess of the exact value is right; close ones only score.
+		}
+	}
+	ps.AnsweredQuestions[question.ID] = true
+
+	return map[string]interface{}{
+		"type":       "numericReveal",
+		"questionId": question.ID,
+		"value":      question.NumericValue(),
+		"unit":       question.Unit,
+		"scoring":    ps.NumericScoring,
+		"guesses":    results,
+	}
+}
+
+// question looks up one of the session's questions by ID. The caller must hold the lock.
+func (ps *PlayerSession) question(questionID string) (models.Question, bool) {
+	for _, question := range ps.Questions {
+		if question.ID == questionID {
+			return question, true
+		}
+	}
+	return models.Question{}, false
+}
@@ -0,0 +1,47 @@
+package session
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestNumericGuessesAreRankedOnceEveryoneGuessed(t *testing.T) {
+	ps := newTestSession(t)
+	value := 330.0
+	ps.Questions = []models.Question{{ID: "1", Type: models.QuestionNumeric, Value: &value, Unit: "metres"}}
+	nearest, far := ps.AddPlayer(), ps.AddPlayer()
+	events := NewStreamSubscriber(16)
+	ps.Subscribe(events)
+
+	if err := ps.SubmitGuess(far.ID, "1", 400); err != nil {
+		t.Fatalf("SubmitGuess failed: %v", err)
+	}
+	ps.RevealSettledGuesses()
+	if far.Score != 0 {
+		t.Fatalf("Guesses should not score before everyone has guessed")
+	}
+	if err := ps.SubmitGuess(far.ID, "1", 330); err != ErrAlreadyAnswered {
+		t.Errorf("Expected a second guess to be refused; got %v", err)
+	}
+
+	ps.SubmitGuess(nearest.ID, "1", 300)
+	ps.RevealSettledGuesses()
+	if nearest.Score != 10 || far.Score != 5 {
+		t.Errorf("Expected the closest guess to score in full and the next half; got %d and %d", nearest.Score, far.Score)
+	}
+	if nearest.Correct != 0 || far.Correct != 0 {
+		t.Errorf("Guesses off the true value should score without counting as correct")
+	}
+
+	revealed := false
+	for len(events.Messages()) > 0 {
+		if strings.Contains(string(<-events.Messages()), `"type":"numericReveal"`) {
+			revealed = true
+		}
+	}
+	if !revealed {
+		t.Errorf("Expected the guesses to be revealed to everyone")
+	}
+}
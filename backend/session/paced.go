@@ -0,0 +1,211 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+const (
+	DefaultTimeLimit      = 20 * time.Second // Time players get per question in server-paced modes.
+	DefaultRevealDuration = 3 * time.Second  // Pause after revealing an answer before the next question.
+)
+
+var (
+	ErrNotCurrentQuestion = errors.New("question is not open for answers")
+	ErrAlreadyAnswered    = errors.New("player already answered this question")
+	ErrNotContestant      = errors.New("player is not playing this question")
+)
+
+// pacedQuestion holds the answers collected for the question currently being asked.
+type pacedQuestion struct {
+	index    int
+	expected map[string]bool // Players who must answer before the time limit.
+	answers  map[string]bool // Player ID to whether their answer was correct.
+	done     chan struct{}   // Closed once every expected player has answered.
+}
+
+// QuestionResult summarizes how the expected players answered a closed question.
+type QuestionResult struct {
+	QuestionID string   `json:"questionId"`
+	Correct    []string `json:"correct"` // Player IDs that answered correctly.
+	Wrong      []string `json:"wrong"`   // Player IDs that answered incorrectly.
+	Missing    []string `json:"missing"` // Player IDs that did not answer in time.
+	Skipped    bool     `json:"skipped"` // The host skipped the question, so it does not count.
+}
+
+// askQuestion pushes a question to every client, waits until all expected players have
+// answered or the time limit passes, then reveals the answer and returns the results. If
+// the host skips the question, it is announced as skipped instead of revealed, and a question
+// skipped while paused holds the game until the host resumes. It returns an error if the
+// session closes first.
+func (ps *PlayerSession) askQuestion(index int, expected map[string]bool) (QuestionResult, error) {
+	ps.Lock()
+	question := ps.Questions[index]
+	current := &pacedQuestion{
+		index:    index,
+		expected: expected,
+		answers:  make(map[string]bool),
+		done:     make(chan struct{}),
+	}
+	ps.current = current
+	ps.asked = index + 1
+	ps.Phase = PhaseQuestion
+	timeLimit := ps.timeLimitFor(question.ID)
+	ps.QuestionEnds = ps.deadline(timeLimit)
+	message := map[string]interface{}{
+		"type":      "question",
+		"index":     index,
+		"total":     len(ps.Questions),
+		"question":  ps.PublicQuestion(question),
+		"timeLimit": int(timeLimit / time.Second),
+	}
+	if round := ps.roundOf(question.ID); round >= 0 {
+		message["round"] = round + 1
+		message["points"] = ps.pointsFor(question.ID)
+	}
+	ps.Unlock()
+
+	ps.Broadcast(message)
+
+	skipped, err := ps.runTimer(timeLimit, current.done)
+	if err != nil {
+		return QuestionResult{}, err
+	}
+
+	ps.Lock()
+	ps.Phase = PhaseReveal
+	ps.current = nil
+	result := QuestionResult{QuestionID: question.ID, Correct: []string{}, Wrong: []string{}, Missing: []string{}, Skipped: skipped}
+	for playerID := range current.expected {
+		correct, answered := current.answers[playerID]
+		switch {
+		case !answered:
+			result.Missing = append(result.Missing, playerID)
+		case correct:
+			result.Correct = append(result.Correct, playerID)
+		default:
+			result.Wrong = append(result.Wrong, playerID)
+		}
+	}
+	ps.Unlock()
+
+	if skipped {
+		ps.Broadcast(map[string]interface{}{"type": "questionSkipped", "questionId": question.ID})
+		if err := ps.awaitResume(); err != nil {
+			return QuestionResult{}, err
+		}
+		return result, nil
+	}
+	reveal := map[string]interface{}{
+		"type":         "reveal",
+		"questionId":   question.ID,
+		"correctIndex": question.CorrectIndex,
+		"results":      result,
+	}
+	switch question.QuestionType() {
+	case models.QuestionMultiSelect:
+		reveal["correctIndexes"] = question.CorrectIndexes
+	case models.QuestionOrdering:
+		reveal["correctOrder"] = question.CorrectOrder
+	case models.QuestionFreeText:
+		if len(question.Answers) > 0 {
+			reveal["answer"] = question.Answers[0] // The canonical answer.
+		}
+	}
+	ps.Broadcast(reveal)
+	return result, nil
+}
+
+// SubmitPacedAnswer records an answer to the question currently being asked.
+func (ps *PlayerSession) SubmitPacedAnswer(playerID, questionID string, correct bool) error {
+	ps.Lock()
+	defer ps.Unlock()
+
+	if ps.Paused {
+		return ErrPaused
+	}
+	current := ps.current
+	if current == nil || ps.Questions[current.index].ID != questionID || time.Now().After(ps.QuestionEnds) {
+		return ErrNotCurrentQuestion
+	}
+	if !current.expected[playerID] {
+		return ErrNotContestant
+	}
+	if _, answered := current.answers[playerID]; answered {
+		return ErrAlreadyAnswered
+	}
+
+	current.answers[playerID] = correct
+	if player, exists := ps.Players[playerID]; exists {
+		ps.markAnswered(player, questionID)
+	}
+	if len(current.answers) == len(current.expected) {
+		close(current.done)
+	}
+	return nil
+}
+
+// reached returns how many of the session's questions, in order, players may see. Classic
+// players read ahead at their own pace, but server-paced questions are only shown once asked,
+// and a buzzer question only once every earlier one is closed, so nobody can study the
+// questions to come. The caller must hold the lock.
+func (ps *PlayerSession) reached() int {
+	if ps.Phase == PhaseComplete {
+		return len(ps.Questions)
+	}
+	switch ps.Mode {
+	case ModeElimination:
+		return ps.asked
+	case ModeBuzzer:
+		if !ps.started() {
+			return 0
+		}
+		for index, question := range ps.Questions {
+			if !ps.buzzerClosed(question.ID) {
+				return index + 1
+			}
+		}
+	}
+	return len(ps.Questions)
+}
+
+// buzzerClosed reports whether a buzzer question is over: won, skipped by the host, or
+// missed by every player. The caller must hold the lock.
+func (ps *PlayerSession) buzzerClosed(questionID string) bool {
+	state, exists := ps.Buzzers[questionID]
+	if ps.AnsweredQuestions[questionID] || (exists && (state.WinnerID != "" || state.Skipped)) {
+		return true
+	}
+	if !exists || len(ps.Players) == 0 {
+		return false
+	}
+	for playerID := range ps.Players {
+		if !state.LockedOut[playerID] {
+			return false
+		}
+	}
+	return true
+}
+
+// Reached reports whether players may see a question yet.
+func (ps *PlayerSession) Reached(questionID string) bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	for _, question := range ps.Questions[:ps.reached()] {
+		if question.ID == questionID {
+			return true
+		}
+	}
+	return false
+}
+
+// currentQuestionID returns the ID of the question being asked, if any. The caller must hold the lock.
+func (ps *PlayerSession) currentQuestionID() string {
+	if ps.current == nil {
+		return ""
+	}
+	return ps.Questions[ps.current.index].ID
+}
@@ -0,0 +1,103 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// ErrReviewNotFound is returned when resolving a review that is not pending.
+var ErrReviewNotFound = errors.New("no pending review with that ID")
+
+// AnswerReview is a borderline free-text answer waiting for the host to accept or reject it.
+type AnswerReview struct {
+	ID         string   `json:"reviewId"`
+	PlayerID   string   `json:"playerId"`
+	PlayerName string   `json:"playerName"`
+	QuestionID string   `json:"questionId"`
+	Text       string   `json:"text"`     // The answer as the player typed it.
+	Accepted   []string `json:"accepted"` // Answers the question accepts.
+}
+
+// HostReviews reports whether borderline free-text answers go to the host. Only classic
+// play has time to wait for a decision; other modes mark them wrong.
+func (ps *PlayerSession) HostReviews() bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	return ps.ReviewAnswers && ps.Mode == ModeClassic && ps.HostID != ""
+}
+
+// QueueReview holds a borderline answer for the host and sends it to them. A player has at
+// most one pending review per question; resubmitting returns the one already queued.
+func (ps *PlayerSession) QueueReview(playerID, questionID, text string, accepted []string) AnswerReview {
+	ps.Lock()
+	for _, pending := range ps.reviews {
+		if pending.PlayerID == playerID && pending.QuestionID == questionID {
+			ps.Unlock()
+			return pending
+		}
+	}
+	review := AnswerReview{
+		ID:         uuid.New().String(),
+		PlayerID:   playerID,
+		QuestionID: questionID,
+		Text:       text,
+		Accepted:   accepted,
+	}
+	if player, exists := ps.Players[playerID]; exists {
+		review.PlayerName = player.Name
+	}
+	ps.reviews[review.ID] = review
+	hostID := ps.HostID
+	ps.Unlock()
+
+	ps.SendToPlayer(hostID, map[string]interface{}{"type": "reviewAnswer", "review": review})
+	return review
+}
+
+// ResolveReview removes a pending review so the caller can apply the host's decision.
+func (ps *PlayerSession) ResolveReview(reviewID string) (AnswerReview, error) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	review, exists := ps.reviews[reviewID]
+	if !exists {
+		return AnswerReview{}, ErrReviewNotFound
+	}
+	delete(ps.reviews, reviewID)
+	return review, nil
+}
+
+// pendingReviews lists the reviews waiting for the host. The caller must hold the lock.
+func (ps *PlayerSession) pendingReviews() []AnswerReview {
+	reviews := make([]AnswerReview, 0, len(ps.reviews))
+	for _, review := range ps.reviews {
+		reviews = append(reviews, review)
+	}
+	return reviews
+}
+
+// SendToPlayer transmits a message to every connection of one player on this node.
+func (ps *PlayerSession) SendToPlayer(playerID string, message interface{}) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Failed to marshal message: %v", err)
+		return
+	}
+
+	for sub, boundPlayerID := range ps.Subscribers {
+		if boundPlayerID != playerID {
+			continue
+		}
+		if err := sub.Send(messageBytes); err != nil {
+			log.Printf("Failed to send message: %v", err)
+			ps.dropSubscriber(sub)
+		}
+	}
+}
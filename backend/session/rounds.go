@@ -0,0 +1,248 @@
+package session
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// BasePoints is what a correct answer scores under standard scoring.
+const BasePoints = 10
+
+// DefaultIntermission is how long server-paced games show the scoreboard between rounds.
+const DefaultIntermission = 10 * time.Second
+
+// ScoringRule selects how many points a correct answer is worth in a round.
+type ScoringRule string
+
+const (
+	ScoringStandard ScoringRule = "standard" // A correct answer scores BasePoints.
+	ScoringDouble   ScoringRule = "double"   // A correct answer scores twice BasePoints, as in a final round.
+)
+
+// ValidScoringRule reports whether rule is a supported round scoring rule.
+func ValidScoringRule(rule ScoringRule) bool {
+	switch rule {
+	case ScoringStandard, ScoringDouble:
+		return true
+	}
+	return false
+}
+
+// Round is one stage of a multi-round game, with its own questions and rules.
+type Round struct {
+	Name        string        `json:"name"`
+	Category    int           `json:"category,omitempty"`   // Open Trivia Database category ID; 0 for any.
+	Difficulty  string        `json:"difficulty,omitempty"` // "easy", "medium" or "hard"; empty for any.
+	QuestionIDs []string      `json:"questionIds"`          // Questions asked in this round, in order.
+	TimeLimit   time.Duration `json:"-"`                    // Time per question in server-paced modes; 0 for the session's. Sent in seconds.
+	Scoring     ScoringRule   `json:"scoring"`
+}
+
+// MarshalJSON sends the round's time limit in whole seconds, as game options give it.
+func (r Round) MarshalJSON() ([]byte, error) {
+	type plain Round
+	return json.Marshal(struct {
+		plain
+		TimeLimit int `json:"timeLimit,omitempty"`
+	}{plain(r), int(r.TimeLimit / time.Second)})
+}
+
+// RoundScore is one player's line on an intermission scoreboard.
+type RoundScore struct {
+	PlayerID   string `json:"playerId"`
+	PlayerName string `json:"playerName"`
+	Score      int    `json:"score"`      // Total across all rounds so far.
+	RoundScore int    `json:"roundScore"` // Points earned in the round just finished.
+}
+
+// ConfigureRounds splits the session's questions into rounds. Each round lists its question IDs.
+func (ps *PlayerSession) ConfigureRounds(rounds []Round) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	ps.Rounds = rounds
+	ps.questionRounds = make(map[string]int)
+	ps.announcedRounds = make(map[int]bool)
+	for i, round := range rounds {
+		for _, questionID := range round.QuestionIDs {
+			ps.questionRounds[questionID] = i
+		}
+	}
+}
+
+// roundOf returns the index of the round a question belongs to, or -1 if the session has no
+// rounds. The caller must hold the lock.
+func (ps *PlayerSession) roundOf(questionID string) int {
+	if round, exists := ps.questionRounds[questionID]; exists {
+		return round
+	}
+	return -1
+}
+
+// RoundOf returns the round a question belongs to, if the session is played in rounds.
+func (ps *PlayerSession) RoundOf(questionID string) (Round, bool) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	round := ps.roundOf(questionID)
+	if round < 0 {
+		return Round{}, false
+	}
+	return ps.Rounds[round], true
+}
+
+// addToRound places a replacement question in the same round as the question it replaces.
+// The caller must hold the lock.
+func (ps *PlayerSession) addToRound(questionID, replacementID string) {
+	round := ps.roundOf(questionID)
+	if round < 0 {
+		return
+	}
+	ps.Rounds[round].QuestionIDs = append(ps.Rounds[round].QuestionIDs, replacementID)
+	ps.questionRounds[replacementID] = round
+}
+
+// PointsFor returns what a correct answer to a question is worth under its round's scoring rule.
+func (ps *PlayerSession) PointsFor(questionID string) int {
+	ps.Lock()
+	defer ps.Unlock()
+
+	return ps.pointsFor(questionID)
+}
+
+// pointsFor is PointsFor for callers holding the lock.
+func (ps *PlayerSession) pointsFor(questionID string) int {
+	if round := ps.roundOf(questionID); round >= 0 && ps.Rounds[round].Scoring == ScoringDouble {
+		return 2 * BasePoints
+	}
+	return BasePoints
+}
+
+// timeLimitFor returns how long players get for a server-paced question. The caller must hold the lock.
+func (ps *PlayerSession) timeLimitFor(questionID string) time.Duration {
+	if round := ps.roundOf(questionID); round >= 0 && ps.Rounds[round].TimeLimit > 0 {
+		return ps.Rounds[round].TimeLimit
+	}
+	return ps.TimeLimit
+}
+
+// endsRound reports whether a question is the last one of a round that is followed by
+// another round. The caller must hold the lock.
+func (ps *PlayerSession) endsRound(questionID string) (int, bool) {
+	round := ps.roundOf(questionID)
+	if round < 0 || round == len(ps.Rounds)-1 {
+		return round, false
+	}
+	questionIDs := ps.Rounds[round].QuestionIDs
+	return round, questionIDs[len(questionIDs)-1] == questionID
+}
+
+// AnnounceFinishedRounds broadcasts the intermission scoreboard for each round every player
+// has now finished, except the last, which ends the game instead. It is for modes where
+// players answer at their own pace; server-paced modes announce rounds as they play them.
+func (ps *PlayerSession) AnnounceFinishedRounds() {
+	ps.Lock()
+	if ps.Mode == ModeElimination {
+		ps.Unlock()
+		return
+	}
+	var finished []int
+	for round := 0; round < len(ps.Rounds)-1; round++ {
+		if !ps.announcedRounds[round] && ps.roundFinished(round) {
+			ps.announcedRounds[round] = true
+			finished = append(finished, round)
+		}
+	}
+	ps.Unlock()
+
+	for _, round := range finished {
+		ps.broadcastIntermission(round, 0)
+	}
+}
+
+// roundFinished reports whether every question of a round is done for every player: answered,
+// skipped, or in buzzer mode closed, whether won, skipped by the host or missed by everyone.
+// The caller must hold the lock.
+func (ps *PlayerSession) roundFinished(round int) bool {
+	for _, questionID := range ps.Rounds[round].QuestionIDs {
+		if ps.solo() {
+			if !ps.questionClosed(questionID) {
+				return false
+			}
+			continue
+		}
+		if ps.Mode == ModeBuzzer {
+			if !ps.buzzerClosed(questionID) {
+				return false
+			}
+			continue
+		}
+		for _, player := range ps.Players {
+			if !player.Answered[questionID] {
+				return false
+			}
+		}
+	}
+	return len(ps.Players) > 0 || ps.solo()
+}
+
+// broadcastIntermission announces the scoreboard after a round. In server-paced modes the
+// next round starts after the given pause; self-paced players carry on when they like.
+func (ps *PlayerSession) broadcastIntermission(round int, pause time.Duration) {
+	ps.Lock()
+	finished := ps.Rounds[round]
+	inRound := make(map[string]bool, len(finished.QuestionIDs))
+	for _, questionID := range finished.QuestionIDs {
+		inRound[questionID] = true
+	}
+
+	scores := make([]RoundScore, 0, len(ps.Players))
+	for _, player := range ps.Players {
+		line := RoundScore{PlayerID: player.ID, PlayerName: player.Name, Score: player.Score}
+		for questionID, points := range player.Points {
+			if inRound[questionID] {
+				line.RoundScore += points
+			}
+		}
+		scores = append(scores, line)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].PlayerName < scores[j].PlayerName
+	})
+	next := ps.Rounds[round+1]
+	next.QuestionIDs = append([]string{}, next.QuestionIDs...)
+	message := map[string]interface{}{
+		"type":      "intermission",
+		"round":     round + 1,
+		"name":      finished.Name,
+		"nextRound": next,
+		"scores":    scores,
+		"duration":  int(pause / time.Second),
+	}
+	if ps.solo() {
+		message["score"] = ps.Score
+	}
+	ps.Unlock()
+
+	if ps.TeamMode {
+		message["teams"] = ps.TeamStandings()
+	}
+	ps.Broadcast(message)
+}
+
+// roundsView returns a copy of the session's rounds. The caller must hold the lock.
+func (ps *PlayerSession) roundsView() []Round {
+	if len(ps.Rounds) == 0 {
+		return nil
+	}
+	rounds := make([]Round, len(ps.Rounds))
+	for i, round := range ps.Rounds {
+		round.QuestionIDs = append([]string{}, round.QuestionIDs...)
+		rounds[i] = round
+	}
+	return rounds
+}
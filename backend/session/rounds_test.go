@@ -0,0 +1,98 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+func TestRoundsScoreByRuleWithIntermission(t *testing.T) {
+	ps := newTestSession(t)
+	ps.Mode = ModeElimination
+	ps.TimeLimit = 200 * time.Millisecond
+	ps.RevealDuration = 0
+	ps.Intermission = 50 * time.Millisecond
+	ps.Questions = []models.Question{{ID: "1"}, {ID: "2"}}
+	ps.ConfigureRounds([]Round{
+		{Name: "Warm-up", QuestionIDs: []string{"1"}, Scoring: ScoringStandard},
+		{Name: "Final", QuestionIDs: []string{"2"}, Scoring: ScoringDouble},
+	})
+	first, second := ps.AddPlayer(), ps.AddPlayer()
+	events := NewStreamSubscriber(64)
+	ps.Subscribe(events)
+
+	done := make(chan struct{})
+	go func() {
+		ps.RunElimination()
+		close(done)
+	}()
+
+	for _, questionID := range []string{"1", "2"} {
+		for _, playerID := range []string{first.ID, second.ID} {
+			for i := 0; i < 100; i++ {
+				if err := ps.SubmitPacedAnswer(playerID, questionID, true); err != ErrNotCurrentQuestion {
+					break
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Game did not finish")
+	}
+	if first.Score != 30 || second.Score != 30 {
+		t.Errorf("Expected 10 points in the first round and 20 in the double round; got %d and %d", first.Score, second.Score)
+	}
+
+	intermissions := 0
+	for len(events.Messages()) > 0 {
+		if strings.Contains(string(<-events.Messages()), `"type":"intermission"`) {
+			intermissions++
+		}
+	}
+	if intermissions != 1 {
+		t.Errorf("Expected one intermission between the two rounds; got %d", intermissions)
+	}
+}
+
+func TestBuzzerRoundEndsOnceEveryQuestionIsClosed(t *testing.T) {
+	ps := newTestSession(t)
+	ps.Mode = ModeBuzzer
+	ps.Phase = PhaseInProgress
+	ps.BuzzWindow = 20 * time.Millisecond
+	ps.Questions = []models.Question{{ID: "1"}, {ID: "2"}}
+	ps.ConfigureRounds([]Round{
+		{Name: "Warm-up", QuestionIDs: []string{"1"}, Scoring: ScoringStandard},
+		{Name: "Final", QuestionIDs: []string{"2"}, Scoring: ScoringDouble, TimeLimit: 30 * time.Second},
+	})
+	first, second := ps.AddPlayer(), ps.AddPlayer()
+	events := NewStreamSubscriber(64)
+	ps.Subscribe(events)
+	ps.BindSubscriber(events, first.ID)
+
+	// One player answers wrong and the other lets their buzz time out.
+	ps.Buzz(first.ID, "1")
+	ps.ResolveBuzz(first.ID, "1", false)
+	ps.Buzz(second.ID, "1")
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case message := <-events.Messages():
+			if !strings.Contains(string(message), `"type":"intermission"`) {
+				continue
+			}
+			if !strings.Contains(string(message), `"timeLimit":30`) {
+				t.Errorf("Expected the next round's time limit in seconds; got %s", message)
+			}
+			return
+		case <-timeout:
+			t.Fatal("Expected an intermission once the question timed out for everyone")
+		}
+	}
+}
@@ -1,33 +1,192 @@
 package session
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
 	"time"
 
+	"github.com/gclluch/TriviaApp-ReactGo/bus"
+	"github.com/gclluch/TriviaApp-ReactGo/chat"
+	"github.com/gclluch/TriviaApp-ReactGo/grading"
+	"github.com/gclluch/TriviaApp-ReactGo/history"
+	"github.com/gclluch/TriviaApp-ReactGo/media"
 	"github.com/gclluch/TriviaApp-ReactGo/models"
 	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
 )
 
+// DefaultReconnectGrace is how long a disconnected player keeps their seat before counting as having left.
+const DefaultReconnectGrace = 30 * time.Second
+
+// Phase describes where a session is in its lifecycle.
+type Phase string
+
+const (
+	PhaseWaiting    Phase = "waiting"    // Session created, countdown not yet started.
+	PhaseCountdown  Phase = "countdown"  // Pre-game countdown is running.
+	PhaseInProgress Phase = "inProgress" // Players are answering questions.
+	PhaseQuestion   Phase = "question"   // A server-paced question is open for answers.
+	PhaseReveal     Phase = "reveal"     // A server-paced question has closed and its answer is shown.
+	PhaseComplete   Phase = "complete"   // Every player has finished.
+)
+
+// Mode selects the rules a session is played under.
+type Mode string
+
+const (
+	ModeClassic     Mode = "classic"     // Players answer at their own pace; the first correct answer scores.
+	ModeBuzzer      Mode = "buzzer"      // Players buzz in first; only the buzzer holder may answer.
+	ModeElimination Mode = "elimination" // Server-paced questions; a wrong or missing answer knocks a player out.
+)
+
+// ValidMode reports whether mode is a supported game mode.
+func ValidMode(mode Mode) bool {
+	switch mode {
+	case ModeClassic, ModeBuzzer, ModeElimination:
+		return true
+	}
+	return false
+}
+
+// ErrPlayerNotFound is returned when an operation names a player not in the session.
+var ErrPlayerNotFound = errors.New("player not found")
+
 // PlayerSession encapsulates the state and operations of a game session.
 type PlayerSession struct {
 	sync.Mutex
+	ID                string                    // Unique identifier of the session.
+	Phase             Phase                     // Current lifecycle phase.
+	Mode              Mode                      // Rules the session is played under.
+	Options           models.GameOptions        // Options the session was created with, for replaying it.
+	CountdownEnds     time.Time                 // When the running countdown reaches zero.
 	Score             int                       // Single player score or multiplayer high score.
+	HostID            string                    // Player who controls the session; the first to join.
 	Players           map[string]*models.Player // Players participating in the session.
-	Connections       map[*websocket.Conn]bool  // Active WebSocket connections.
+	Subscribers       map[Subscriber]string     // Active event subscribers and the player ID bound to each, if any.
 	Questions         []models.Question         // Map of question ID to Question.
 	AnsweredQuestions map[string]bool           // Tracks if a question has been answered correctly.
+	ResumeTokens      map[string]string         // Maps resume tokens to player IDs.
+	Chat              *chat.Room                // In-game chat and reactions.
+	TeamMode          bool                      // Players compete in teams.
+	Teams             []string                  // Team names in team mode.
+	TeamScoring       TeamScoring               // How member scores combine into team scores.
+	BuzzWindow        time.Duration             // How long a buzzer holder has to answer in buzzer mode.
+	Buzzers           map[string]*BuzzState     // Buzzer state per question ID in buzzer mode.
+	TimeLimit         time.Duration             // Time per question in server-paced modes.
+	QuestionEnds      time.Time                 // When the server-paced question being asked closes.
+	RevealDuration    time.Duration             // Pause after each reveal in server-paced modes.
+	Lifelines         map[Lifeline]int          // Lifelines left in a single-player game.
+	LifelineUses      []LifelineUse             // Lifelines spent so far, in order.
+	soloCredit        map[string]float64        // Single-player answers by question ID; the share of the points earned.
+	SpectatorDelay    time.Duration             // How far spectators' events lag behind the live game.
+	spectators        map[Subscriber]Subscriber // Spectator connections to the subscriber registered for each.
+	watchers          map[Subscriber]Subscriber // Connections not bound to a player to the delayed subscriber registered for each.
+	Paused            bool                      // The host has paused the game.
+	pausedAt          time.Time                 // When the game was paused.
+	timer             *phaseTimer               // Times the running countdown, question or reveal.
+	ctx               context.Context           // Cancelled when the session closes, stopping its timers.
+	cancel            context.CancelFunc        // Cancels ctx.
+	current           *pacedQuestion            // Server-paced question being asked, if any.
+	asked             int                       // Server-paced questions pushed to players so far.
+	contestants       map[string]bool           // Players who started an elimination game.
+	Rounds            []Round                   // Rounds the game is played in; empty for a single flat round.
+	AnswerTolerance   int                       // Edits a free-text answer may be off by and still count.
+	ReviewAnswers     bool                      // Borderline free-text answers go to the host instead of being marked wrong.
+	reviews           map[string]AnswerReview   // Borderline answers waiting for the host, by review ID.
+	NumericScoring    grading.NumericScoring    // How numeric questions award points in multiplayer games.
+	Media             *media.Library            // Hands out URLs for question media; nil sends questions without it.
+	History           *history.History          // Questions each returning player has answered; nil keeps none.
+	SoloUserID        string                    // User playing a single-player game, whose answers go in their history.
+	guesses           map[string]playerGuesses  // Numeric question ID to the guesses made, until revealed.
+	revealedGuesses   map[string]bool           // Numeric questions whose guesses have been scored.
+	Intermission      time.Duration             // Scoreboard pause between rounds in server-paced modes.
+	questionRounds    map[string]int            // Question ID to the index of its round.
+	announcedRounds   map[int]bool              // Rounds whose intermission has been broadcast in self-paced modes.
+	Relay             bool                      // Session is owned by another node; this copy only relays its events.
+	ReconnectGrace    time.Duration             // How long a player whose connection was dropped may take to resume.
+	leaveTimers       map[string]*time.Timer    // Pending removals for players who lost their connection.
+	events            bus.Bus                   // Bus carrying this session's events between nodes.
+	stopEvents        func()                    // Cancels the bus subscription.
+}
+
+// Snapshot captures the session state a reconnecting player needs to resume play.
+type Snapshot struct {
+	Type              string                 `json:"type"`               // Always "snapshot".
+	SessionID         string                 `json:"sessionId"`          // Identifier of the session.
+	Phase             Phase                  `json:"phase"`              // Current lifecycle phase.
+	Mode              Mode                   `json:"mode"`               // Rules the session is played under.
+	CurrentQuestion   string                 `json:"currentQuestion"`    // ID of the player's next unanswered question, empty when done.
+	RemainingTime     int                    `json:"remainingTime"`      // Seconds left on the running countdown or question; frozen while paused.
+	Scores            map[string]int         `json:"scores"`             // Player name to score.
+	AnsweredQuestions []string               `json:"answeredQuestions"`  // IDs of questions the player has already answered.
+	Player            *models.Player         `json:"player"`             // The resuming player.
+	HostID            string                 `json:"hostId"`             // Player who controls the session.
+	ChatEnabled       bool                   `json:"chatEnabled"`        // Whether chat is currently on.
+	ChatHistory       []chat.Message         `json:"chatHistory"`        // Recent chat messages, oldest first.
+	Teams             []TeamStanding         `json:"teams,omitempty"`    // Team standings in team mode.
+	Question          *models.PublicQuestion `json:"question,omitempty"` // Question being asked in server-paced modes.
+	Spectators        int                    `json:"spectators"`         // Spectators watching through this node.
+	Paused            bool                   `json:"paused"`             // Whether the host has paused the game.
+	Rounds            []Round                `json:"rounds,omitempty"`   // Rounds the game is played in, if any.
+	Reviews           []AnswerReview         `json:"reviews,omitempty"`  // Answers waiting for review; sent to the host only.
+}
+
+// EventTopic returns the bus topic carrying a session's broadcast events.
+func EventTopic(sessionID string) string {
+	return "session:" + sessionID + ":events"
 }
 
-// NewPlayerSession initializes a new session with default values.
-func NewPlayerSession() *PlayerSession {
-	return &PlayerSession{
+// NewPlayerSession initializes a new session with default values and subscribes it to
+// its event topic, so broadcasts published by any node reach its local subscribers.
+func NewPlayerSession(id string, events bus.Bus) (*PlayerSession, error) {
+	ps := &PlayerSession{
+		ID:                id,
+		Phase:             PhaseWaiting,
+		Mode:              ModeClassic,
 		Players:           make(map[string]*models.Player),
-		Connections:       make(map[*websocket.Conn]bool),
+		Subscribers:       make(map[Subscriber]string),
 		AnsweredQuestions: make(map[string]bool),
+		ResumeTokens:      make(map[string]string),
+		Chat:              chat.NewRoom(chat.DefaultConfig()),
+		BuzzWindow:        DefaultBuzzWindow,
+		TimeLimit:         DefaultTimeLimit,
+		RevealDuration:    DefaultRevealDuration,
+		Intermission:      DefaultIntermission,
+		AnswerTolerance:   grading.DefaultTolerance,
+		reviews:           make(map[string]AnswerReview),
+		NumericScoring:    grading.ByRank,
+		guesses:           make(map[string]playerGuesses),
+		revealedGuesses:   make(map[string]bool),
+		questionRounds:    make(map[string]int),
+		announcedRounds:   make(map[int]bool),
+		Buzzers:           make(map[string]*BuzzState),
+		Lifelines:         make(map[Lifeline]int),
+		soloCredit:        make(map[string]float64),
+		spectators:        make(map[Subscriber]Subscriber),
+		watchers:          make(map[Subscriber]Subscriber),
+		ReconnectGrace:    DefaultReconnectGrace,
+		leaveTimers:       make(map[string]*time.Timer),
+		events:            events,
+	}
+	ps.ctx, ps.cancel = context.WithCancel(context.Background())
+
+	stop, err := events.Subscribe(EventTopic(id), ps.deliver)
+	if err != nil {
+		ps.cancel()
+		return nil, err
+	}
+	ps.stopEvents = stop
+	return ps, nil
+}
+
+// Close detaches the session from the event bus and stops its timers.
+func (ps *PlayerSession) Close() {
+	ps.cancel()
+	if ps.stopEvents != nil {
+		ps.stopEvents()
 	}
 }
 
@@ -39,15 +198,66 @@ func (ps *PlayerSession) UpdateScore(scoreToAdd int) {
 	ps.Score += scoreToAdd
 }
 
-// UpdatePlayerScore adjusts a player's score within a session.
+// UpdatePlayerScore adds the points for a correct answer to a player's score within a session.
 func (ps *PlayerSession) UpdatePlayerScore(playerID, questionID string, scoreToAdd int) {
+	ps.AddCredit(playerID, questionID, scoreToAdd, true)
+}
+
+// AddCredit adds points to a player's score for a question. Only fully correct answers count
+// towards the player's correct answers; partly-right ones just score.
+func (ps *PlayerSession) AddCredit(playerID, questionID string, points int, correct bool) {
 	ps.Lock()
 	defer ps.Unlock()
 
 	if player, exists := ps.Players[playerID]; exists {
-		player.Score += scoreToAdd
-		ps.AnsweredQuestions[questionID] = true // Mark the question as answered
+		ps.addPoints(player, questionID, points, correct)
+	}
+}
+
+// ScoreFirstCorrect awards the points for a correct answer unless the question was already
+// scored: by anyone, or in team mode by this player. It reports whether points were awarded.
+func (ps *PlayerSession) ScoreFirstCorrect(playerID, questionID string, points int) bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	player, exists := ps.Players[playerID]
+	if !exists {
+		return false
+	}
+	scored := ps.AnsweredQuestions[questionID]
+	if ps.TeamMode {
+		_, scored = player.Points[questionID]
+	}
+	if scored {
+		return false
+	}
+	ps.addPoints(player, questionID, points, true)
+	return true
+}
+
+// addPoints adds to a player's score for a question. The caller must hold the lock.
+func (ps *PlayerSession) addPoints(player *models.Player, questionID string, points int, correct bool) {
+	player.Score += points
+	player.Points[questionID] += points
+	if correct {
+		player.Correct++
+	}
+	player.LastScoredAt = time.Now()
+	ps.AnsweredQuestions[questionID] = true // Mark the question as answered
+}
+
+// MarkAnswered records that a player has submitted an answer for a question, right or wrong.
+// It reports whether this was the player's first answer to the question.
+func (ps *PlayerSession) MarkAnswered(playerID, questionID string) bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	player, exists := ps.Players[playerID]
+	if !exists || player.Answered[questionID] {
+		return false
 	}
+	ps.markAnswered(player, questionID)
+	return true
 }
 
 // AddPlayer introduces a new player to the session.
@@ -57,41 +267,290 @@ func (ps *PlayerSession) AddPlayer() *models.Player {
 
 	playerID := uuid.New().String()
 	playerName := fmt.Sprintf("Player %d", len(ps.Players)+1)
-	player := &models.Player{ID: playerID, Name: playerName}
+	player := &models.Player{
+		ID:          playerID,
+		Name:        playerName,
+		ResumeToken: uuid.New().String(),
+		Answered:    make(map[string]bool),
+		Points:      make(map[string]int),
+	}
 
 	ps.Players[playerID] = player
+	ps.ResumeTokens[player.ResumeToken] = playerID
+	if ps.HostID == "" {
+		ps.HostID = playerID
+	}
 	return player
 }
 
-// AddConnection manages a new WebSocket connection.
-func (ps *PlayerSession) AddConnection(conn *websocket.Conn) {
+// ResumePlayer looks up the player owning a resume token and cancels any pending removal.
+func (ps *PlayerSession) ResumePlayer(token string) (*models.Player, bool) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	player, exists := ps.Players[ps.ResumeTokens[token]]
+	if !exists {
+		return nil, false
+	}
+
+	if timer, pending := ps.leaveTimers[player.ID]; pending {
+		timer.Stop()
+		delete(ps.leaveTimers, player.ID)
+		log.Printf("Player %s reconnected to session %s", player.ID, ps.ID)
+	}
+	return player, true
+}
+
+// Subscribe registers a new event subscriber, whatever its transport. Until BindSubscriber
+// ties it to a player, the connection is only watching, so its events are held back by the
+// spectator delay like a spectator's.
+func (ps *PlayerSession) Subscribe(sub Subscriber) {
 	ps.Lock()
-	ps.Connections[conn] = true
+	_, exists := ps.Subscribers[sub]
+	if _, watching := ps.watchers[sub]; !exists && !watching {
+		registered := ps.delayed(sub)
+		if registered != sub {
+			ps.watchers[sub] = registered
+		}
+		ps.Subscribers[registered] = ""
+	}
 	ps.Unlock()
 	log.Println("New player connected.")
 }
 
-// Broadcast transmits messages to all active WebSocket connections.
-func (ps *PlayerSession) Broadcast(message interface{}) {
+// BindSubscriber associates a subscriber with a player so a dropped connection can be tracked.
+// A player's connection gets events live; any events still held back for it are discarded,
+// so callers should send the player a snapshot.
+func (ps *PlayerSession) BindSubscriber(sub Subscriber, playerID string) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	if registered, watching := ps.watchers[sub]; watching {
+		delete(ps.watchers, sub)
+		delete(ps.Subscribers, registered)
+		registered.(closer).Close()
+	}
+	ps.Subscribers[sub] = playerID
+}
+
+// Unsubscribe drops an event subscriber. If it was the last connection of a player,
+// the player is removed from the session unless they resume within the grace window.
+func (ps *PlayerSession) Unsubscribe(sub Subscriber, grace time.Duration) {
+	if ps.unsubscribeSpectator(sub) {
+		ps.BroadcastSpectatorCount()
+		return
+	}
+
+	ps.Lock()
+	defer ps.Unlock()
+
+	if registered, watching := ps.watchers[sub]; watching {
+		delete(ps.watchers, sub)
+		delete(ps.Subscribers, registered)
+		registered.(closer).Close()
+		return
+	}
+	ps.release(sub, grace)
+}
+
+// release forgets a subscriber and, if it was the last connection of a player, starts the
+// grace window after which the player leaves. The caller must hold the lock.
+func (ps *PlayerSession) release(sub Subscriber, grace time.Duration) {
+	playerID := ps.Subscribers[sub]
+	delete(ps.Subscribers, sub)
+	ps.leaveUnlessConnected(playerID, grace)
+}
+
+// ExpectConnection starts the grace window for a player who resumed without a connection,
+// such as over HTTP: unless one binds to them in time, the player leaves as if they had
+// disconnected.
+func (ps *PlayerSession) ExpectConnection(playerID string) {
 	ps.Lock()
 	defer ps.Unlock()
 
+	ps.leaveUnlessConnected(playerID, ps.ReconnectGrace)
+}
+
+// leaveUnlessConnected starts the grace window after which a player with no connection bound
+// to them leaves. The caller must hold the lock.
+func (ps *PlayerSession) leaveUnlessConnected(playerID string, grace time.Duration) {
+	if playerID == "" || ps.Phase == PhaseComplete {
+		return
+	}
+
+	for _, boundID := range ps.Subscribers {
+		if boundID == playerID {
+			return // Player is still connected elsewhere.
+		}
+	}
+
+	if timer, pending := ps.leaveTimers[playerID]; pending {
+		timer.Stop()
+	}
+	ps.leaveTimers[playerID] = time.AfterFunc(grace, func() {
+		ps.removePlayer(playerID)
+	})
+}
+
+// removePlayer deletes a player whose grace window expired and announces the new player count.
+func (ps *PlayerSession) removePlayer(playerID string) {
+	ps.Lock()
+	player, exists := ps.Players[playerID]
+	if exists {
+		delete(ps.Players, playerID)
+		delete(ps.ResumeTokens, player.ResumeToken)
+		if ps.HostID == playerID {
+			// Hand control to any remaining player.
+			ps.HostID = ""
+			for remainingID := range ps.Players {
+				ps.HostID = remainingID
+				break
+			}
+		}
+	}
+	delete(ps.leaveTimers, playerID)
+	ps.Unlock()
+
+	if exists {
+		log.Printf("Player %s left session %s", playerID, ps.ID)
+		ps.BroadcastPlayerCount()
+		ps.RevealSettledGuesses()
+	}
+}
+
+// started reports whether play has begun. The caller must hold the lock.
+func (ps *PlayerSession) started() bool {
+	return ps.Phase != PhaseWaiting && ps.Phase != PhaseCountdown
+}
+
+// Complete marks the session as finished so dropped connections no longer count as leaving.
+func (ps *PlayerSession) Complete() {
+	ps.Lock()
+	defer ps.Unlock()
+
+	ps.Phase = PhaseComplete
+}
+
+// Snapshot builds the full state a player needs after reconnecting.
+func (ps *PlayerSession) Snapshot(playerID string) Snapshot {
+	snapshot := ps.snapshot(playerID)
+	if ps.TeamMode {
+		snapshot.Teams = ps.TeamStandings()
+	}
+	return snapshot
+}
+
+// snapshot builds the parts of a Snapshot read directly from session state.
+func (ps *PlayerSession) snapshot(playerID string) Snapshot {
+	ps.Lock()
+	defer ps.Unlock()
+
+	snapshot := Snapshot{
+		Type:              "snapshot",
+		SessionID:         ps.ID,
+		Phase:             ps.Phase,
+		Mode:              ps.Mode,
+		Scores:            make(map[string]int),
+		AnsweredQuestions: []string{},
+		HostID:            ps.HostID,
+		ChatEnabled:       ps.Chat.Enabled(),
+		ChatHistory:       ps.Chat.History(),
+		Spectators:        len(ps.spectators),
+		Paused:            ps.Paused,
+		Rounds:            ps.roundsView(),
+	}
+
+	snapshot.RemainingTime = int(ps.remaining().Round(time.Second) / time.Second)
+
+	for _, player := range ps.Players {
+		snapshot.Scores[player.Name] = player.Score
+	}
+
+	if playerID != "" && playerID == ps.HostID {
+		snapshot.Reviews = ps.pendingReviews()
+	}
+
+	if player, exists := ps.Players[playerID]; exists {
+		snapshot.Player = player
+		for _, question := range ps.Questions {
+			if player.Answered[question.ID] {
+				snapshot.AnsweredQuestions = append(snapshot.AnsweredQuestions, question.ID)
+			} else if snapshot.CurrentQuestion == "" {
+				snapshot.CurrentQuestion = question.ID
+			}
+		}
+	}
+
+	// In server-paced modes everyone is on the question the server is asking.
+	if ps.current != nil {
+		question := ps.PublicQuestion(ps.Questions[ps.current.index])
+		snapshot.CurrentQuestion = question.ID
+		snapshot.Question = &question
+	}
+	return snapshot
+}
+
+// SendTo transmits a message to a single subscriber.
+func (ps *PlayerSession) SendTo(sub Subscriber, message interface{}) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Failed to marshal message: %v", err)
+		return
+	}
+
+	if registered, watching := ps.watchers[sub]; watching {
+		sub = registered // Keep it in order with the connection's delayed events.
+	}
+	if err := sub.Send(messageBytes); err != nil {
+		log.Printf("Failed to send message: %v", err)
+		ps.dropSubscriber(sub)
+	}
+}
+
+// Broadcast publishes a message on the session's event topic. Every node relays it to its
+// local subscribers, and every transport receives the same encoded event.
+func (ps *PlayerSession) Broadcast(message interface{}) {
 	messageBytes, err := json.Marshal(message)
 	if err != nil {
 		log.Printf("Failed to marshal message: %v", err)
 		return
 	}
 
-	for conn := range ps.Connections {
-		if err := conn.WriteMessage(websocket.TextMessage, messageBytes); err != nil {
+	if err := ps.events.Publish(EventTopic(ps.ID), messageBytes); err != nil {
+		log.Printf("Failed to publish message: %v", err)
+	}
+}
+
+// deliver transmits an event received from the bus to all subscribers connected to this node.
+func (ps *PlayerSession) deliver(messageBytes []byte) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	for sub := range ps.Subscribers {
+		if err := sub.Send(messageBytes); err != nil {
 			log.Printf("Failed to send message: %v", err)
-			delete(ps.Connections, conn)
+			ps.dropSubscriber(sub)
 		}
 	}
 }
 
+// SubscriberCount returns how many connections receive this session's events through this node.
+func (ps *PlayerSession) SubscriberCount() int {
+	ps.Lock()
+	defer ps.Unlock()
+
+	return len(ps.Subscribers)
+}
+
 // BroadcastPlayerCount sends the current player count to all clients.
+// Relay sessions skip it, since only the owning node knows the players.
 func (ps *PlayerSession) BroadcastPlayerCount() {
+	if ps.Relay {
+		return
+	}
 	message := map[string]interface{}{"type": "playerCount", "count": len(ps.Players)}
 	ps.Broadcast(message)
 }
@@ -109,24 +568,39 @@ func (ps *PlayerSession) CheckAllPlayersFinished() bool {
 	return true
 }
 
-// StartCountdown initiates a countdown, broadcasting updates to all clients.
-func (ps *PlayerSession) StartCountdown(duration int) {
+// StartCountdown runs the pre-game countdown, broadcasting each second to all clients.
+// The countdown stops while the game is paused; it returns an error if the session closes first.
+func (ps *PlayerSession) StartCountdown(duration int) error {
+	ps.Lock()
+	ps.Phase = PhaseCountdown
+	ps.CountdownEnds = ps.deadline(time.Duration(duration) * time.Second)
+	ps.Unlock()
+
 	for i := duration; i >= 0; i-- {
 		ps.Broadcast(map[string]interface{}{"type": "countdown", "time": i})
-		time.Sleep(time.Second)
+		if _, err := ps.runTimer(time.Second, nil); err != nil {
+			return err
+		}
 	}
+
+	ps.Lock()
+	if ps.Phase == PhaseCountdown {
+		ps.Phase = PhaseInProgress
+	}
+	ps.Unlock()
+	return nil
 }
 
 // BroadcastHighScore announces the session's high score to all clients.
 func (ps *PlayerSession) BroadcastHighScore() {
-	// ps.Lock()
-	// defer ps.Unlock()
-
+	ps.Lock()
 	highScore := 0
 	for _, player := range ps.Players {
 		if player.Score > highScore {
 			highScore = player.Score
 		}
 	}
+	ps.Unlock()
+
 	ps.Broadcast(map[string]interface{}{"type": "highScore", "score": highScore})
 }
@@ -0,0 +1,181 @@
+package session
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gclluch/TriviaApp-ReactGo/bus"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestSession creates a session on an in-process bus.
+func newTestSession(t *testing.T) *PlayerSession {
+	ps, err := NewPlayerSession("test", bus.NewLocalBus())
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	return ps
+}
+
+func TestResumeWithinGraceKeepsPlayer(t *testing.T) {
+	ps := newTestSession(t)
+	ps.Questions = []models.Question{{ID: "1"}, {ID: "2"}}
+	player := ps.AddPlayer()
+	ps.MarkAnswered(player.ID, "1")
+
+	sub := NewStreamSubscriber(1)
+	ps.Subscribe(sub)
+	ps.BindSubscriber(sub, player.ID)
+	ps.Unsubscribe(sub, 50*time.Millisecond)
+
+	resumed, ok := ps.ResumePlayer(player.ResumeToken)
+	if !ok || resumed.ID != player.ID {
+		t.Fatalf("Expected resume token to map to player %s", player.ID)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, exists := ps.Players[player.ID]; !exists {
+		t.Fatalf("Player resumed within the grace window should not be removed")
+	}
+
+	snapshot := ps.Snapshot(player.ID)
+	if snapshot.CurrentQuestion != "2" {
+		t.Errorf("Expected current question 2; got %q", snapshot.CurrentQuestion)
+	}
+	if len(snapshot.AnsweredQuestions) != 1 || snapshot.AnsweredQuestions[0] != "1" {
+		t.Errorf("Expected answered questions [1]; got %v", snapshot.AnsweredQuestions)
+	}
+}
+
+func TestResumeWithoutAConnectionStillExpires(t *testing.T) {
+	ps := newTestSession(t)
+	ps.ReconnectGrace = 50 * time.Millisecond
+	player := ps.AddPlayer()
+
+	resumed, _ := ps.ResumePlayer(player.ResumeToken)
+	ps.ExpectConnection(resumed.ID)
+	time.Sleep(100 * time.Millisecond)
+
+	ps.Lock()
+	_, exists := ps.Players[player.ID]
+	ps.Unlock()
+	if exists {
+		t.Fatalf("A player who resumed without connecting should leave after the grace window")
+	}
+}
+
+func TestDisconnectPastGraceRemovesPlayer(t *testing.T) {
+	ps := newTestSession(t)
+	player := ps.AddPlayer()
+
+	sub := NewStreamSubscriber(1)
+	ps.Subscribe(sub)
+	ps.BindSubscriber(sub, player.ID)
+	ps.Unsubscribe(sub, 10*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := ps.ResumePlayer(player.ResumeToken); ok {
+		t.Fatalf("Resume token should be invalid once the grace window has passed")
+	}
+}
+
+func TestSubscriberDroppedForFallingBehindGetsGrace(t *testing.T) {
+	ps := newTestSession(t)
+	ps.ReconnectGrace = 10 * time.Millisecond
+	player := ps.AddPlayer()
+
+	sub := NewStreamSubscriber(1)
+	ps.Subscribe(sub)
+	ps.BindSubscriber(sub, player.ID)
+	ps.SendTo(sub, map[string]interface{}{"type": "first"})
+	ps.SendTo(sub, map[string]interface{}{"type": "overflow"})
+
+	ps.Lock()
+	_, seated := ps.Players[player.ID]
+	ps.Unlock()
+	if !seated {
+		t.Fatalf("Player should keep their seat during the grace window")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := ps.ResumePlayer(player.ResumeToken); ok {
+		t.Fatalf("Player should leave once the grace window has passed")
+	}
+}
+
+func TestBroadcastReachesEverySubscriber(t *testing.T) {
+	ps := newTestSession(t)
+	first, second := NewStreamSubscriber(1), NewStreamSubscriber(1)
+	ps.Subscribe(first)
+	ps.Subscribe(second)
+
+	ps.Broadcast(map[string]interface{}{"type": "countdown", "time": 3})
+
+	a, b := <-first.Messages(), <-second.Messages()
+	if string(a) != string(b) || string(a) != `{"time":3,"type":"countdown"}` {
+		t.Errorf("Expected identical countdown events; got %s and %s", a, b)
+	}
+}
+
+func TestBroadcastReachesRelayOnAnotherNode(t *testing.T) {
+	server := miniredis.RunT(t)
+	newNodeBus := func() bus.Bus {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return bus.NewRedisBus(client)
+	}
+
+	owner, err := NewPlayerSession("shared", newNodeBus())
+	if err != nil {
+		t.Fatalf("Failed to create owner session: %v", err)
+	}
+	relay, err := NewPlayerSession("shared", newNodeBus())
+	if err != nil {
+		t.Fatalf("Failed to create relay session: %v", err)
+	}
+	defer owner.Close()
+	defer relay.Close()
+
+	sub := NewStreamSubscriber(1)
+	relay.Subscribe(sub)
+	owner.Broadcast(map[string]interface{}{"type": "sessionComplete"})
+
+	select {
+	case message := <-sub.Messages():
+		if string(message) != `{"type":"sessionComplete"}` {
+			t.Errorf("Unexpected message: %s", message)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Broadcast on the owning node never reached the relay's subscriber")
+	}
+}
+
+func TestOnlyTheFirstAnswerEarnsPartialCredit(t *testing.T) {
+	ps := newTestSession(t)
+	ps.Questions = []models.Question{{ID: "1", Type: models.QuestionMultiSelect}}
+	player := ps.AddPlayer()
+
+	var wg sync.WaitGroup
+	var first atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ps.MarkAnswered(player.ID, "1") {
+				first.Add(1)
+				ps.AddCredit(player.ID, "1", 5, false)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if first.Load() != 1 || player.Score != 5 {
+		t.Errorf("Expected exactly one first answer to score; got %d scoring %d", first.Load(), player.Score)
+	}
+	if player.Correct != 0 {
+		t.Errorf("Partial credit should not count as a correct answer")
+	}
+}
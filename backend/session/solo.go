@@ -0,0 +1,103 @@
+package session
+
+import (
+	"math"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// Solo reports whether the session is a single-player classic game: nobody has joined it
+// as a player, and answers are submitted without a player ID.
+func (ps *PlayerSession) Solo() bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	return ps.solo()
+}
+
+// solo is Solo for callers holding the lock.
+func (ps *PlayerSession) solo() bool {
+	return len(ps.Players) == 0 && ps.Mode == ModeClassic
+}
+
+// SubmitSoloAnswer records a single-player answer earning the given share of the question's
+// points: 1 for a correct answer, 0 for a wrong one, and in between for partial credit. Each
+// question can be answered once, and a skipped question cannot be answered at all.
+func (ps *PlayerSession) SubmitSoloAnswer(questionID string, credit float64) error {
+	ps.Lock()
+	defer ps.Unlock()
+
+	if ps.questionClosed(questionID) {
+		return ErrQuestionAnswered
+	}
+	ps.soloCredit[questionID] = credit
+	ps.Score += ps.soloPoints(questionID)
+	ps.remember(ps.SoloUserID, questionID)
+	return nil
+}
+
+// soloPoints returns the points a single-player answer earned. The caller must hold the lock.
+func (ps *PlayerSession) soloPoints(questionID string) int {
+	return int(math.Round(float64(ps.pointsFor(questionID)) * ps.soloCredit[questionID]))
+}
+
+// questionClosed reports whether a single-player question was answered or skipped. The caller must hold the lock.
+func (ps *PlayerSession) questionClosed(questionID string) bool {
+	if _, answered := ps.soloCredit[questionID]; answered {
+		return true
+	}
+	for _, use := range ps.LifelineUses {
+		if use.QuestionID == questionID && use.Lifeline == LifelineSkip {
+			return true
+		}
+	}
+	return false
+}
+
+// SoloFinished reports whether every question of a single-player game was answered or skipped.
+func (ps *PlayerSession) SoloFinished() bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	for _, question := range ps.Questions {
+		if !ps.questionClosed(question.ID) {
+			return false
+		}
+	}
+	return len(ps.Questions) > 0
+}
+
+// PlayedQuestions returns the questions of a single-player game that were not skipped, in
+// order. In a game played in rounds, a skip's replacement stays in the skipped question's
+// round, so each round keeps its number of questions.
+func (ps *PlayerSession) PlayedQuestions() []models.Question {
+	ps.Lock()
+	defer ps.Unlock()
+
+	skipped := make(map[string]bool)
+	for _, use := range ps.LifelineUses {
+		if use.Lifeline == LifelineSkip {
+			skipped[use.QuestionID] = true
+		}
+	}
+
+	order := ps.Questions
+	if len(ps.Rounds) > 0 {
+		order = make([]models.Question, 0, len(ps.Questions))
+		for _, round := range ps.Rounds {
+			for _, questionID := range round.QuestionIDs {
+				if question, exists := ps.question(questionID); exists {
+					order = append(order, question)
+				}
+			}
+		}
+	}
+
+	played := make([]models.Question, 0, len(order))
+	for _, question := range order {
+		if !skipped[question.ID] {
+			played = append(played, question)
+		}
+	}
+	return played
+}
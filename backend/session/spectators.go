@@ -0,0 +1,93 @@
+package session
+
+// spectatorBuffer is how many delayed events a spectator may have in flight.
+const spectatorBuffer = 256
+
+// closer is implemented by subscribers that hold resources until closed.
+type closer interface {
+	Close()
+}
+
+// SubscribeSpectator registers a connection that watches without playing. Spectators get
+// every broadcast, held back by the session's spectator delay, and are counted apart from
+// players. It returns the subscriber events actually go through, which callers should use
+// to send the spectator anything else so it arrives in order with the delayed stream.
+func (ps *PlayerSession) SubscribeSpectator(sub Subscriber) Subscriber {
+	ps.Lock()
+	defer ps.Unlock()
+
+	if registered, exists := ps.spectators[sub]; exists {
+		return registered
+	}
+
+	registered := ps.delayed(sub)
+	ps.spectators[sub] = registered
+	ps.Subscribers[registered] = ""
+	return registered
+}
+
+// delayed returns the subscriber to register for a connection that is not a player's, which
+// holds its events back by the spectator delay. The caller must hold the lock.
+func (ps *PlayerSession) delayed(sub Subscriber) Subscriber {
+	if ps.SpectatorDelay > 0 {
+		return NewDelayedSubscriber(sub, ps.SpectatorDelay, spectatorBuffer)
+	}
+	return sub
+}
+
+// unsubscribeSpectator drops a spectator's connection, reporting whether sub was one.
+func (ps *PlayerSession) unsubscribeSpectator(sub Subscriber) bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	registered, exists := ps.spectators[sub]
+	if !exists {
+		return false
+	}
+	delete(ps.spectators, sub)
+	delete(ps.Subscribers, registered)
+	if registered != sub {
+		if delayed, ok := registered.(closer); ok {
+			delayed.Close()
+		}
+	}
+	return true
+}
+
+// dropSubscriber forgets a subscriber that can no longer receive events. A player losing their
+// last connection this way gets the same grace window to resume as one who disconnected.
+// The caller must hold the lock.
+func (ps *PlayerSession) dropSubscriber(sub Subscriber) {
+	for watcher, registered := range ps.spectators {
+		if registered == sub {
+			delete(ps.spectators, watcher)
+			delete(ps.Subscribers, sub)
+			return
+		}
+	}
+	for watcher, registered := range ps.watchers {
+		if registered == sub {
+			delete(ps.watchers, watcher)
+			delete(ps.Subscribers, sub)
+			return
+		}
+	}
+	ps.release(sub, ps.ReconnectGrace)
+}
+
+// SpectatorCount returns how many spectators are watching through this node.
+func (ps *PlayerSession) SpectatorCount() int {
+	ps.Lock()
+	defer ps.Unlock()
+
+	return len(ps.spectators)
+}
+
+// BroadcastSpectatorCount sends the current spectator count to all clients.
+// Relay sessions skip it, since they only know their own node's spectators.
+func (ps *PlayerSession) BroadcastSpectatorCount() {
+	if ps.Relay {
+		return
+	}
+	ps.Broadcast(map[string]interface{}{"type": "spectatorCount", "count": ps.SpectatorCount()})
+}
@@ -0,0 +1,48 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpectatorsAreDelayedAndCountedApart(t *testing.T) {
+	ps := newTestSession(t)
+	ps.SpectatorDelay = 100 * time.Millisecond
+	player := ps.AddPlayer()
+
+	live := NewStreamSubscriber(4)
+	ps.Subscribe(live)
+	ps.BindSubscriber(live, player.ID)
+	watcher := NewStreamSubscriber(4)
+	ps.SubscribeSpectator(watcher)
+	unbound := NewStreamSubscriber(4) // Joined without resuming as a player.
+	ps.Subscribe(unbound)
+
+	if ps.SpectatorCount() != 1 || len(ps.Players) != 1 {
+		t.Fatalf("Spectators should be counted apart from players")
+	}
+
+	ps.Broadcast(map[string]string{"type": "reveal"})
+	select {
+	case <-live.Messages():
+	default:
+		t.Errorf("Players should receive events immediately")
+	}
+	select {
+	case <-watcher.Messages():
+		t.Errorf("Spectators should not receive events before the delay")
+	case <-unbound.Messages():
+		t.Errorf("Connections not bound to a player should not receive events before the delay")
+	default:
+	}
+	select {
+	case <-watcher.Messages():
+	case <-time.After(time.Second):
+		t.Errorf("Spectators should receive events after the delay")
+	}
+
+	ps.Unsubscribe(watcher, time.Minute)
+	if ps.SpectatorCount() != 0 {
+		t.Errorf("Spectator count should drop when a spectator leaves")
+	}
+}
@@ -0,0 +1,171 @@
+package session
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrSubscriberClosed is returned when sending to a subscriber that can no longer receive events.
+var ErrSubscriberClosed = errors.New("subscriber closed")
+
+// Subscriber receives broadcast events for a session, independent of the transport carrying them.
+type Subscriber interface {
+	Send(message []byte) error // Delivers a single JSON-encoded event.
+}
+
+// WebSocketSubscriber delivers events over a WebSocket connection.
+type WebSocketSubscriber struct {
+	mutex sync.Mutex
+	Conn  *websocket.Conn
+}
+
+// NewWebSocketSubscriber wraps a WebSocket connection as a session subscriber.
+func NewWebSocketSubscriber(conn *websocket.Conn) *WebSocketSubscriber {
+	return &WebSocketSubscriber{Conn: conn}
+}
+
+// Send writes the event as a text frame. Writes are serialized because WebSocket
+// connections support only one concurrent writer.
+func (ws *WebSocketSubscriber) Send(message []byte) error {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	return ws.Conn.WriteMessage(websocket.TextMessage, message)
+}
+
+// StreamSubscriber buffers events for transports that pull them from a channel,
+// such as Server-Sent Events.
+type StreamSubscriber struct {
+	mutex    sync.Mutex
+	closed   bool
+	messages chan []byte
+}
+
+// NewStreamSubscriber creates a subscriber holding up to buffer undelivered events.
+func NewStreamSubscriber(buffer int) *StreamSubscriber {
+	return &StreamSubscriber{messages: make(chan []byte, buffer)}
+}
+
+// Send queues the event. A subscriber that falls a full buffer behind is treated as gone.
+func (ss *StreamSubscriber) Send(message []byte) error {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	if ss.closed {
+		return ErrSubscriberClosed
+	}
+
+	select {
+	case ss.messages <- message:
+		return nil
+	default:
+		ss.closed = true
+		close(ss.messages)
+		return ErrSubscriberClosed
+	}
+}
+
+// Messages returns the channel events are delivered on. It is closed when the subscriber is closed.
+func (ss *StreamSubscriber) Messages() <-chan []byte {
+	return ss.messages
+}
+
+// Close stops delivery and releases the channel.
+func (ss *StreamSubscriber) Close() {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	if !ss.closed {
+		ss.closed = true
+		close(ss.messages)
+	}
+}
+
+// delayedMessage is an event waiting out a DelayedSubscriber's delay.
+type delayedMessage struct {
+	sendAt  time.Time
+	message []byte
+}
+
+// DelayedSubscriber holds events back for a fixed delay before passing them on, in order,
+// so a streamed game can be watched without revealing it live.
+type DelayedSubscriber struct {
+	mutex  sync.Mutex
+	closed bool
+	target Subscriber
+	delay  time.Duration
+	queue  chan delayedMessage
+	done   chan struct{}
+}
+
+// NewDelayedSubscriber wraps target so each event reaches it delay after being sent,
+// holding up to buffer events in flight.
+func NewDelayedSubscriber(target Subscriber, delay time.Duration, buffer int) *DelayedSubscriber {
+	ds := &DelayedSubscriber{
+		target: target,
+		delay:  delay,
+		queue:  make(chan delayedMessage, buffer),
+		done:   make(chan struct{}),
+	}
+	go ds.run()
+	return ds
+}
+
+// Send queues the event. A subscriber whose buffer fills up, or whose target failed, is treated as gone.
+func (ds *DelayedSubscriber) Send(message []byte) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	if ds.closed {
+		return ErrSubscriberClosed
+	}
+
+	select {
+	case ds.queue <- delayedMessage{sendAt: time.Now().Add(ds.delay), message: message}:
+		return nil
+	default:
+		ds.close()
+		return ErrSubscriberClosed
+	}
+}
+
+// Close stops delivery; queued events are discarded.
+func (ds *DelayedSubscriber) Close() {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.close()
+}
+
+// close is Close for callers holding the lock.
+func (ds *DelayedSubscriber) close() {
+	if !ds.closed {
+		ds.closed = true
+		close(ds.done)
+	}
+}
+
+// run passes queued events on to the target once their delay has passed.
+func (ds *DelayedSubscriber) run() {
+	for {
+		select {
+		case <-ds.done:
+			return
+		case pending := <-ds.queue:
+			timer := time.NewTimer(time.Until(pending.sendAt))
+			select {
+			case <-ds.done:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			if err := ds.target.Send(pending.message); err != nil {
+				ds.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package session
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// TeamScoring selects how member scores combine into a team score.
+type TeamScoring string
+
+const (
+	TeamScoringSum     TeamScoring = "sum"     // Total of every member's score.
+	TeamScoringAverage TeamScoring = "average" // Mean member score, so team size doesn't matter.
+	TeamScoringBest    TeamScoring = "best"    // For each question, the best member's points.
+)
+
+// DefaultTeams are used when a team-mode session is created without team names.
+var DefaultTeams = []string{"Red", "Blue"}
+
+var (
+	ErrUnknownTeam = errors.New("unknown team")
+	ErrTeamsLocked = errors.New("teams can only change before the game starts")
+)
+
+// TeamStanding is a team's aggregated result.
+type TeamStanding struct {
+	Name         string    `json:"name"`
+	Score        float64   `json:"score"`
+	Correct      int       `json:"correct"` // Questions scored on by any member, used to break ties.
+	Members      []string  `json:"members"` // Names of the team's players.
+	Rank         int       `json:"rank"`    // 1 for the winners; tied teams share a rank.
+	lastScoredAt time.Time // When the team last scored; earlier wins a tie.
+}
+
+// ValidTeamScoring reports whether scoring is a supported rule.
+func ValidTeamScoring(scoring TeamScoring) bool {
+	switch scoring {
+	case TeamScoringSum, TeamScoringAverage, TeamScoringBest:
+		return true
+	}
+	return false
+}
+
+// ConfigureTeams switches the session to team mode.
+func (ps *PlayerSession) ConfigureTeams(teams []string, scoring TeamScoring) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	ps.TeamMode = true
+	ps.Teams = teams
+	ps.TeamScoring = scoring
+}
+
+// AssignTeam puts a player on a team. An empty team name auto-balances the player onto
+// the team with the fewest members. Players may switch teams only until the game starts.
+func (ps *PlayerSession) AssignTeam(playerID, team string) error {
+	ps.Lock()
+	defer ps.Unlock()
+
+	player, exists := ps.Players[playerID]
+	if !exists {
+		return ErrPlayerNotFound
+	}
+	if player.Team != "" && ps.started() {
+		return ErrTeamsLocked
+	}
+
+	if team == "" {
+		team = ps.smallestTeam(playerID)
+	} else if !ps.hasTeam(team) {
+		return ErrUnknownTeam
+	}
+	player.Team = team
+	return nil
+}
+
+// HasTeam reports whether the session has a team with the given name.
+func (ps *PlayerSession) HasTeam(team string) bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	return ps.hasTeam(team)
+}
+
+// hasTeam is HasTeam for callers holding the lock.
+func (ps *PlayerSession) hasTeam(team string) bool {
+	for _, name := range ps.Teams {
+		if name == team {
+			return true
+		}
+	}
+	return false
+}
+
+// smallestTeam returns the team with the fewest members other than the given player,
+// preferring earlier teams on a tie. The caller must hold the lock.
+func (ps *PlayerSession) smallestTeam(excludePlayerID string) string {
+	sizes := make(map[string]int)
+	for _, player := range ps.Players {
+		if player.ID != excludePlayerID && player.Team != "" {
+			sizes[player.Team]++
+		}
+	}
+
+	smallest := ps.Teams[0]
+	for _, name := range ps.Teams[1:] {
+		if sizes[name] < sizes[smallest] {
+			smallest = name
+		}
+	}
+	return smallest
+}
+
+// TeamStandings aggregates member scores with the session's scoring rule and ranks the
+// teams. Ties on score are broken by questions scored, then by who reached the score first.
+func (ps *PlayerSession) TeamStandings() []TeamStanding {
+	ps.Lock()
+	defer ps.Unlock()
+
+	standings := make([]TeamStanding, 0, len(ps.Teams))
+	for _, name := range ps.Teams {
+		standing := TeamStanding{Name: name, Members: []string{}}
+		best := make(map[string]int)
+		total := 0
+
+		for _, player := range ps.Players {
+			if player.Team != name {
+				continue
+			}
+			standing.Members = append(standing.Members, player.Name)
+			total += player.Score
+			if player.LastScoredAt.After(standing.lastScoredAt) {
+				standing.lastScoredAt = player.LastScoredAt
+			}
+			for questionID, points := range player.Points {
+				if points > best[questionID] {
+					best[questionID] = points
+				}
+			}
+		}
+		sort.Strings(standing.Members)
+		standing.Correct = len(best)
+
+		switch ps.TeamScoring {
+		case TeamScoringAverage:
+			if len(standing.Members) > 0 {
+				standing.Score = math.Round(float64(total)/float64(len(standing.Members))*100) / 100
+			}
+		case TeamScoringBest:
+			for _, points := range best {
+				standing.Score += float64(points)
+			}
+		default:
+			standing.Score = float64(total)
+		}
+		standings = append(standings, standing)
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return compareStandings(standings[i], standings[j]) < 0
+	})
+	for i := range standings {
+		if i > 0 && compareStandings(standings[i-1], standings[i]) == 0 {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+	return standings
+}
+
+// compareStandings orders a ahead of b (negative), behind b (positive) or level (zero).
+func compareStandings(a, b TeamStanding) int {
+	switch {
+	case a.Score != b.Score:
+		if a.Score > b.Score {
+			return -1
+		}
+		return 1
+	case a.Correct != b.Correct:
+		return b.Correct - a.Correct
+	case !a.lastScoredAt.Equal(b.lastScoredAt):
+		// A team that never scored has a zero time but should not win on it.
+		if a.lastScoredAt.IsZero() || (!b.lastScoredAt.IsZero() && b.lastScoredAt.Before(a.lastScoredAt)) {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// BroadcastTeamScores announces the current team standings to all clients.
+func (ps *PlayerSession) BroadcastTeamScores() {
+	ps.Broadcast(map[string]interface{}{"type": "teamScores", "teams": ps.TeamStandings()})
+}
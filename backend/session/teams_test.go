@@ -0,0 +1,43 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTeamStandingsScoringAndTieBreak(t *testing.T) {
+	ps := newTestSession(t)
+	ps.ConfigureTeams([]string{"Red", "Blue"}, TeamScoringBest)
+
+	red1, red2, blue := ps.AddPlayer(), ps.AddPlayer(), ps.AddPlayer()
+	ps.AssignTeam(red1.ID, "Red")
+	ps.AssignTeam(red2.ID, "Red")
+	ps.AssignTeam(blue.ID, "") // Auto-balanced onto the smaller team.
+	if blue.Team != "Blue" {
+		t.Fatalf("Expected auto-balance onto Blue; got %q", blue.Team)
+	}
+
+	// Both Red members score on the same question; best-answer counts it once.
+	ps.UpdatePlayerScore(red1.ID, "1", 10)
+	ps.UpdatePlayerScore(red2.ID, "1", 10)
+	time.Sleep(time.Millisecond)
+	ps.UpdatePlayerScore(blue.ID, "2", 10)
+
+	standings := ps.TeamStandings()
+	if standings[0].Name != "Red" || standings[0].Score != 10 || standings[1].Score != 10 {
+		t.Fatalf("Expected Red to win a 10-10 tie by scoring first; got %+v", standings)
+	}
+	if standings[0].Rank != 1 || standings[1].Rank != 2 {
+		t.Errorf("Expected the tie to be broken; got ranks %d and %d", standings[0].Rank, standings[1].Rank)
+	}
+
+	ps.TeamScoring = TeamScoringSum
+	if standings = ps.TeamStandings(); standings[0].Name != "Red" || standings[0].Score != 20 {
+		t.Errorf("Expected Red to lead on sum with 20; got %+v", standings[0])
+	}
+
+	ps.TeamScoring = TeamScoringAverage
+	if standings = ps.TeamStandings(); standings[0].Score != 10 || standings[1].Score != 10 || standings[0].Name != "Red" {
+		t.Errorf("Expected a 10-10 average broken in Red's favour; got %+v", standings)
+	}
+}
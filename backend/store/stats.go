@@ -0,0 +1,58 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// AnswerStats counts the answers given to each question across sessions. Questions and
+// options are keyed by text, since IDs and option order differ from session to session.
+type AnswerStats struct {
+	sync.Mutex
+	counts map[string]map[string]int // Question text to option text to times chosen.
+}
+
+// NewAnswerStats initializes empty answer statistics.
+func NewAnswerStats() *AnswerStats {
+	return &AnswerStats{counts: make(map[string]map[string]int)}
+}
+
+// Record counts one answer to a question. Out-of-range answers are ignored.
+func (s *AnswerStats) Record(question models.Question, answerIndex int) {
+	if answerIndex < 0 || answerIndex >= len(question.Options) {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	options, exists := s.counts[question.QuestionText]
+	if !exists {
+		options = make(map[string]int)
+		s.counts[question.QuestionText] = options
+	}
+	options[question.Options[answerIndex]]++
+}
+
+// Distribution returns the percentage of recorded answers that chose each of the question's
+// options, in the question's option order, along with the number of answers recorded.
+func (s *AnswerStats) Distribution(question models.Question) ([]int, int) {
+	s.Lock()
+	defer s.Unlock()
+
+	options := s.counts[question.QuestionText]
+	total := 0
+	for _, option := range question.Options {
+		total += options[option]
+	}
+
+	distribution := make([]int, len(question.Options))
+	if total == 0 {
+		return distribution, 0
+	}
+	for i, option := range question.Options {
+		distribution[i] = options[option] * 100 / total
+	}
+	return distribution, total
+}
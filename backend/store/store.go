@@ -1,56 +1,303 @@
 package store
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
 	"sync"
+	"time"
 
+	"github.com/gclluch/TriviaApp-ReactGo/bus"
+	"github.com/gclluch/TriviaApp-ReactGo/chat"
+	"github.com/gclluch/TriviaApp-ReactGo/dedupe"
+	"github.com/gclluch/TriviaApp-ReactGo/grading"
+	"github.com/gclluch/TriviaApp-ReactGo/history"
+	"github.com/gclluch/TriviaApp-ReactGo/media"
+	"github.com/gclluch/TriviaApp-ReactGo/models"
 	"github.com/gclluch/TriviaApp-ReactGo/services"
 
 	"github.com/gclluch/TriviaApp-ReactGo/session"
 	"github.com/google/uuid"
 )
 
+// OwnershipTTL is how long a node's claim on a session lasts without renewal.
+const OwnershipTTL = 30 * time.Second
+
 // SessionStore manages player sessions and provides thread-safe operations to manipulate sessions.
 type SessionStore struct {
 	sync.Mutex
-	Sessions map[string]*session.PlayerSession
+	NodeID    string                            // Identifies this node when claiming session ownership.
+	Sessions  map[string]*session.PlayerSession // Sessions owned by this node.
+	Relays    map[string]*session.PlayerSession // Event relays for sessions owned by other nodes.
+	attached  map[string]time.Time              // When each relay was last handed to a client.
+	Events    bus.Bus                           // Carries session events between nodes.
+	Locker    bus.Locker                        // Coordinates which node owns each session.
+	Chat      chat.Config                       // Chat limits and moderation for new sessions.
+	Questions services.QuestionProvider         // Source of questions for new sessions and skips.
+	Stats     *AnswerStats                      // Answers given to each question, for ask-the-audience.
+	Tolerance int                               // Edits a free-text answer may be off by in new sessions.
+	Media     *media.Library                    // Question media store; nil serves no media.
+	History   *history.History                  // Questions each returning player has answered; nil keeps none.
+	Grace     time.Duration                     // How long a player in a new session may take to resume after their connection drops.
+	stop      context.CancelFunc                // Stops renewing ownership.
 }
 
-// NewSessionStore initializes a new instance of SessionStore necessary defaults.
+// NewSessionStore initializes a single-node SessionStore backed by in-process bus and locker.
 func NewSessionStore() *SessionStore {
-	return &SessionStore{
-		Sessions: make(map[string]*session.PlayerSession),
-	}
+	return NewDistributedSessionStore(uuid.New().String(), bus.NewLocalBus(), bus.NewLocalLocker())
 }
 
-// CreateSession creates a new game session with a subset of questions and returns its unique ID.
-// It shuffles the questions and selects the specified number to include in the session.
-func (s *SessionStore) CreateSession(numQuestions int) (string, error) {
-	if numQuestions <= 0 {
-		return "", fmt.Errorf("numQuestions must be positive")
+// NewDistributedSessionStore initializes a SessionStore that shares events and session
+// ownership with other nodes through the given bus and locker.
+func NewDistributedSessionStore(nodeID string, events bus.Bus, locker bus.Locker) *SessionStore {
+	s := &SessionStore{
+		NodeID:    nodeID,
+		Sessions:  make(map[string]*session.PlayerSession),
+		Relays:    make(map[string]*session.PlayerSession),
+		attached:  make(map[string]time.Time),
+		Events:    events,
+		Locker:    locker,
+		Chat:      chat.DefaultConfig(),
+		Questions: services.OpenTDBProvider{},
+		Stats:     NewAnswerStats(),
+		Tolerance: grading.DefaultTolerance,
+		Grace:     session.DefaultReconnectGrace,
 	}
+	ctx, stop := context.WithCancel(context.Background())
+	s.stop = stop
+	go s.renewOwnership(ctx)
+	return s
+}
+
+// Close stops renewing ownership of this node's sessions and closes its relays. The sessions'
+// claims lapse after OwnershipTTL.
+func (s *SessionStore) Close() {
+	s.stop()
 
 	s.Lock()
 	defer s.Unlock()
+	for sessionID, relay := range s.Relays {
+		relay.Close()
+		delete(s.Relays, sessionID)
+		delete(s.attached, sessionID)
+	}
+}
+
+// ownerKey returns the locker key recording which node owns a session.
+func ownerKey(sessionID string) string {
+	return "session:" + sessionID + ":owner"
+}
+
+// ErrInvalidOptions is returned when a session is requested with unusable options.
+var ErrInvalidOptions = errors.New("invalid game options")
+
+// ValidateOptions checks the requested options and fills in defaults.
+func ValidateOptions(options *models.GameOptions) error {
+	if options.QuestionType == "" {
+		options.QuestionType = models.QuestionMultiple
+	}
+	if !validQuestionType(options.QuestionType) {
+		return fmt.Errorf("%w: unknown questionType %q", ErrInvalidOptions, options.QuestionType)
+	}
+	if len(options.Rounds) > 0 {
+		if err := validateRounds(options); err != nil {
+			return err
+		}
+	}
+	if options.NumQuestions <= 0 {
+		return fmt.Errorf("%w: numQuestions must be positive", ErrInvalidOptions)
+	}
+
+	if options.Mode == "" {
+		options.Mode = string(session.ModeClassic)
+	}
+	if !session.ValidMode(session.Mode(options.Mode)) {
+		return fmt.Errorf("%w: unknown mode %q", ErrInvalidOptions, options.Mode)
+	}
+	// Numeric guesses are ranked once every player has guessed, which only self-paced play allows.
+	if options.Mode != string(session.ModeClassic) && asksNumeric(*options) {
+		return fmt.Errorf("%w: numeric questions are only played in classic mode", ErrInvalidOptions)
+	}
+	// Only server-paced play has a clock for a round's time limit to set.
+	if options.Mode != string(session.ModeElimination) && roundsTimed(*options) {
+		return fmt.Errorf("%w: rounds[].timeLimit only applies in elimination mode", ErrInvalidOptions)
+	}
+	if options.NumericScoring == "" {
+		options.NumericScoring = string(grading.ByRank)
+	}
+	if !grading.ValidNumericScoring(grading.NumericScoring(options.NumericScoring)) {
+		return fmt.Errorf("%w: unknown numericScoring %q", ErrInvalidOptions, options.NumericScoring)
+	}
+	if options.BuzzWindow < 0 || options.TimeLimit < 0 || options.SpectatorDelay < 0 {
+		return fmt.Errorf("%w: buzzWindow, timeLimit and spectatorDelay must not be negative", ErrInvalidOptions)
+	}
+
+	for lifeline, count := range options.Lifelines {
+		if !session.ValidLifeline(session.Lifeline(lifeline)) {
+			return fmt.Errorf("%w: unknown lifeline %q", ErrInvalidOptions, lifeline)
+		}
+		if count < 0 || count > session.MaxLifelineUses {
+			return fmt.Errorf("%w: lifeline counts must be between 0 and %d", ErrInvalidOptions, session.MaxLifelineUses)
+		}
+	}
+
+	if options.TeamMode {
+		if len(options.Teams) == 0 {
+			options.Teams = session.DefaultTeams
+		}
+		seen := make(map[string]bool)
+		for _, team := range options.Teams {
+			if team == "" || seen[team] {
+				return fmt.Errorf("%w: team names must be unique and non-empty", ErrInvalidOptions)
+			}
+			seen[team] = true
+		}
+		if len(options.Teams) < 2 {
+			return fmt.Errorf("%w: team mode needs at least two teams", ErrInvalidOptions)
+		}
+		if options.TeamScoring == "" {
+			options.TeamScoring = string(session.TeamScoringSum)
+		}
+		if !session.ValidTeamScoring(session.TeamScoring(options.TeamScoring)) {
+			return fmt.Errorf("%w: unknown teamScoring %q", ErrInvalidOptions, options.TeamScoring)
+		}
+	}
+	return nil
+}
+
+// CreateSession creates a new game session with a subset of questions and returns its unique ID.
+// It shuffles the questions and selects the specified number to include in the session. Games
+// played in rounds fetch each round's questions from its category and difficulty, leaving out
+// any that repeat a question from an earlier round. Questions the expected players have
+// answered before are left out when there are enough others.
+func (s *SessionStore) CreateSession(options models.GameOptions) (string, error) {
+	if err := ValidateOptions(&options); err != nil {
+		return "", err
+	}
+
+	seen := s.SeenQuestions(options.UserIDs)
+	if len(options.Rounds) == 0 {
+		questions, err := s.Questions.FetchQuestions(services.Query{Amount: options.NumQuestions, Type: queryType(options.QuestionType), Exclude: seen})
+		if err != nil {
+			return "", err
+		}
+		return s.CreateSessionWithQuestions(options, questions)
+	}
+
+	var questions []models.Question
+	asked := dedupe.NewIndex()
+	for _, round := range options.Rounds {
+		roundQuestions, err := s.Questions.FetchQuestions(services.Query{
+			Amount:     round.NumQuestions,
+			Category:   round.Category,
+			Difficulty: round.Difficulty,
+			Type:       queryType(round.QuestionType),
+			Exclude:    seen,
+		})
+		if err != nil {
+			return "", err
+		}
+		var fresh []models.Question
+		for _, question := range roundQuestions {
+			if _, repeats := asked.Find(question); !repeats && len(fresh) < round.NumQuestions {
+				fresh = append(fresh, question)
+			}
+		}
+		if len(fresh) < round.NumQuestions {
+			return "", fmt.Errorf("only %d questions available for round %q", len(fresh), round.Name)
+		}
+		for _, question := range fresh {
+			asked.Add(question)
+		}
+		questions = append(questions, fresh...)
+	}
+	// Each round's questions are numbered from 1, so renumber them across the game.
+	for i := range questions {
+		questions[i].ID = strconv.Itoa(i + 1)
+	}
+	return s.CreateSessionWithQuestions(options, questions)
+}
+
+// CreateSessionWithQuestions creates a new game session playing the given questions, in
+// order, and returns its unique ID. Games played in rounds take their questions in round order.
+func (s *SessionStore) CreateSessionWithQuestions(options models.GameOptions, questions []models.Question) (string, error) {
+	options.NumQuestions = len(questions)
+	if err := ValidateOptions(&options); err != nil {
+		return "", err
+	}
+	// With rounds, the number of questions is the rounds' total.
+	if options.NumQuestions != len(questions) {
+		return "", fmt.Errorf("%w: rounds need %d questions, got %d", ErrInvalidOptions, options.NumQuestions, len(questions))
+	}
 
 	// Generate a unique session ID.
 	sessionID := uuid.New().String()
 
-	questions, err := services.FetchQuestions(numQuestions)
+	acquired, err := s.Locker.Acquire(ownerKey(sessionID), s.NodeID, OwnershipTTL)
 	if err != nil {
 		return "", err
 	}
+	if !acquired {
+		return "", fmt.Errorf("session %s is already owned by another node", sessionID)
+	}
 
-	playerSession := session.NewPlayerSession()
+	playerSession, err := session.NewPlayerSession(sessionID, s.Events)
+	if err != nil {
+		s.Locker.Release(ownerKey(sessionID), s.NodeID)
+		return "", err
+	}
 
 	playerSession.Questions = questions
+	playerSession.Options = options
+	playerSession.Chat = chat.NewRoom(s.Chat)
+	playerSession.Mode = session.Mode(options.Mode)
+	if options.BuzzWindow > 0 {
+		playerSession.BuzzWindow = time.Duration(options.BuzzWindow) * time.Second
+	}
+	if options.TimeLimit > 0 {
+		playerSession.TimeLimit = time.Duration(options.TimeLimit) * time.Second
+	}
+	playerSession.SpectatorDelay = time.Duration(options.SpectatorDelay) * time.Second
+	playerSession.AnswerTolerance = s.Tolerance
+	playerSession.Media = s.Media
+	playerSession.History = s.History
+	playerSession.ReconnectGrace = s.Grace
+	playerSession.ReviewAnswers = options.ReviewAnswers
+	playerSession.NumericScoring = grading.NumericScoring(options.NumericScoring)
+	if options.TeamMode {
+		playerSession.ConfigureTeams(options.Teams, session.TeamScoring(options.TeamScoring))
+	}
+	if len(options.Lifelines) > 0 {
+		inventory := make(map[session.Lifeline]int, len(options.Lifelines))
+		for lifeline, count := range options.Lifelines {
+			inventory[session.Lifeline(lifeline)] = count
+		}
+		playerSession.ConfigureLifelines(inventory)
+	}
+
+	if len(options.Rounds) > 0 {
+		playerSession.ConfigureRounds(sessionRounds(options.Rounds, questions))
+	}
 
+	s.Lock()
 	s.Sessions[sessionID] = playerSession
+	s.Unlock()
 
 	fmt.Println(playerSession.Questions)
 	return sessionID, nil
 }
 
+// SeenQuestions returns the keys of the questions any of the given users has answered, or nil
+// if no history is kept.
+func (s *SessionStore) SeenQuestions(userIDs []string) map[string]bool {
+	if s.History == nil || len(userIDs) == 0 {
+		return nil
+	}
+	return s.History.Seen(userIDs...)
+}
+
 // GetSession retrieves a session by its unique ID, returning the session and a boolean indicating if it was found.
 func (s *SessionStore) GetSession(sessionID string) (*session.PlayerSession, bool) {
 	s.Lock()
@@ -59,3 +306,203 @@ func (s *SessionStore) GetSession(sessionID string) (*session.PlayerSession, boo
 	session, exists := s.Sessions[sessionID]
 	return session, exists
 }
+
+// Owner returns the ID of the node owning a session, or "" if no live node owns it.
+func (s *SessionStore) Owner(sessionID string) (string, error) {
+	return s.Locker.Owner(ownerKey(sessionID))
+}
+
+// AttachSession returns the session a client wants to receive events from. Sessions owned by
+// this node are returned directly; for sessions owned by another node, a relay session is
+// returned that forwards the owner's broadcasts to subscribers connected here.
+func (s *SessionStore) AttachSession(sessionID string) (*session.PlayerSession, bool) {
+	if playerSession, exists := s.GetSession(sessionID); exists {
+		return playerSession, true
+	}
+
+	owner, err := s.Owner(sessionID)
+	if err != nil {
+		log.Printf("Failed to look up owner of session %s: %v", sessionID, err)
+		return nil, false
+	}
+	if owner == "" {
+		return nil, false
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.attached[sessionID] = time.Now()
+	if relay, exists := s.Relays[sessionID]; exists {
+		return relay, true
+	}
+	relay, err := session.NewPlayerSession(sessionID, s.Events)
+	if err != nil {
+		log.Printf("Failed to relay session %s: %v", sessionID, err)
+		return nil, false
+	}
+	relay.Relay = true
+	s.Relays[sessionID] = relay
+	return relay, true
+}
+
+// renewOwnership periodically extends this node's claim on the sessions it owns and closes
+// relays no one is listening to, until ctx is cancelled. A session whose claim was lost is
+// no longer served, since another node may now own it.
+func (s *SessionStore) renewOwnership(ctx context.Context) {
+	ticker := time.NewTicker(OwnershipTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.closeIdleRelays(OwnershipTTL / 3)
+		s.Lock()
+		sessionIDs := make([]string, 0, len(s.Sessions))
+		for sessionID := range s.Sessions {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+		s.Unlock()
+
+		for _, sessionID := range sessionIDs {
+			acquired, err := s.Locker.Acquire(ownerKey(sessionID), s.NodeID, OwnershipTTL)
+			if err != nil {
+				log.Printf("Failed to renew ownership of session %s: %v", sessionID, err)
+			} else if !acquired {
+				log.Printf("Lost ownership of session %s; no longer serving it", sessionID)
+				s.dropSession(sessionID)
+			}
+		}
+	}
+}
+
+// dropSession stops serving a session owned by this node.
+func (s *SessionStore) dropSession(sessionID string) {
+	s.Lock()
+	playerSession, exists := s.Sessions[sessionID]
+	delete(s.Sessions, sessionID)
+	s.Unlock()
+
+	if exists {
+		playerSession.Close()
+	}
+}
+
+// closeIdleRelays closes the relays with no subscribers that no client has attached to within
+// the given time, so a client attaching does not lose its relay before subscribing.
+func (s *SessionStore) closeIdleRelays(idle time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	for sessionID, relay := range s.Relays {
+		if relay.SubscriberCount() > 0 || time.Since(s.attached[sessionID]) < idle {
+			continue
+		}
+		relay.Close()
+		delete(s.Relays, sessionID)
+		delete(s.attached, sessionID)
+	}
+}
+
+// validateRounds checks each round's options, fills in default scoring, and sets the number
+// of questions to the rounds' total.
+func validateRounds(options *models.GameOptions) error {
+	total := 0
+	for i := range options.Rounds {
+		round := &options.Rounds[i]
+		if round.Name == "" {
+			round.Name = fmt.Sprintf("Round %d", i+1)
+		}
+		if round.NumQuestions <= 0 {
+			return fmt.Errorf("%w: every round needs a positive numQuestions", ErrInvalidOptions)
+		}
+		if round.Category < 0 || round.TimeLimit < 0 {
+			return fmt.Errorf("%w: round category and timeLimit must not be negative", ErrInvalidOptions)
+		}
+		switch round.Difficulty {
+		case "", "easy", "medium", "hard":
+		default:
+			return fmt.Errorf("%w: unknown difficulty %q", ErrInvalidOptions, round.Difficulty)
+		}
+		if round.Scoring == "" {
+			round.Scoring = string(session.ScoringStandard)
+		}
+		if round.QuestionType == "" {
+			round.QuestionType = options.QuestionType
+		}
+		if !validQuestionType(round.QuestionType) {
+			return fmt.Errorf("%w: unknown questionType %q", ErrInvalidOptions, round.QuestionType)
+		}
+		if !session.ValidScoringRule(session.ScoringRule(round.Scoring)) {
+			return fmt.Errorf("%w: unknown scoring %q", ErrInvalidOptions, round.Scoring)
+		}
+		total += round.NumQuestions
+	}
+	options.NumQuestions = total
+	return nil
+}
+
+// sessionRounds assigns the questions to rounds in order, each taking its numQuestions.
+func sessionRounds(options []models.RoundOptions, questions []models.Question) []session.Round {
+	rounds := make([]session.Round, len(options))
+	next := 0
+	for i, round := range options {
+		rounds[i] = session.Round{
+			Name:       round.Name,
+			Category:   round.Category,
+			Difficulty: round.Difficulty,
+			TimeLimit:  time.Duration(round.TimeLimit) * time.Second,
+			Scoring:    session.ScoringRule(round.Scoring),
+		}
+		for _, question := range questions[next : next+round.NumQuestions] {
+			rounds[i].QuestionIDs = append(rounds[i].QuestionIDs, question.ID)
+		}
+		next += round.NumQuestions
+	}
+	return rounds
+}
+
+// validQuestionType reports whether a game or round may ask for questions of the given type.
+func validQuestionType(questionType string) bool {
+	switch questionType {
+	case models.QuestionMultiple, models.QuestionBoolean, models.QuestionFreeText, models.QuestionNumeric,
+		models.QuestionMultiSelect, models.QuestionOrdering, models.QuestionMixed:
+		return true
+	}
+	return false
+}
+
+// queryType turns a requested question type into a provider filter; mixed games take any type.
+func queryType(questionType string) string {
+	if questionType == models.QuestionMixed {
+		return ""
+	}
+	return questionType
+}
+
+// roundsTimed reports whether any of the game's rounds sets its own time limit.
+func roundsTimed(options models.GameOptions) bool {
+	for _, round := range options.Rounds {
+		if round.TimeLimit > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// asksNumeric reports whether the game or any of its rounds asks numeric questions.
+func asksNumeric(options models.GameOptions) bool {
+	if options.QuestionType == models.QuestionNumeric {
+		return true
+	}
+	for _, round := range options.Rounds {
+		if round.QuestionType == models.QuestionNumeric {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package tournament
+
+import "sort"
+
+// bracketSize returns the smallest power of two that fits the given number of players.
+func bracketSize(players int) int {
+	size := 1
+	for size < players {
+		size *= 2
+	}
+	return size
+}
+
+// bracketPairs pairs seeded entrants for the first round of a bracket of the given size, so
+// the top seeds can only meet in the late rounds: 1 plays the lowest seed, 2 the second
+// lowest and so on. Seeds beyond the field are byes for the players they would meet.
+func bracketPairs(t *Tournament, size int) [][]string {
+	// Build the standard seed order: 1 2 becomes 1 4 2 3, then 1 8 4 5 2 7 3 6, and so on.
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+
+	bySeed := make(map[int]string, len(t.Entrants))
+	for _, entrant := range t.Entrants {
+		bySeed[entrant.Seed] = entrant.ID
+	}
+
+	var pairs [][]string
+	for i := 0; i+1 < len(order); i += 2 {
+		var pair []string
+		for _, seed := range order[i : i+2] {
+			if id, exists := bySeed[seed]; exists {
+				pair = append(pair, id)
+			}
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// rankSwiss orders entrants by wins, then total points, then seed, and ranks them;
+// entrants level on wins and points share a rank.
+func (t *Tournament) rankSwiss() {
+	sort.SliceStable(t.Entrants, func(i, j int) bool { return swissAhead(t.Entrants[i], t.Entrants[j]) })
+	for i := range t.Entrants {
+		if i > 0 && t.Entrants[i-1].Wins == t.Entrants[i].Wins && t.Entrants[i-1].Points == t.Entrants[i].Points {
+			t.Entrants[i].Rank = t.Entrants[i-1].Rank
+		} else {
+			t.Entrants[i].Rank = i + 1
+		}
+	}
+}
+
+// swissAhead reports whether a stands ahead of b in Swiss standings.
+func swissAhead(a, b Entrant) bool {
+	if a.Wins != b.Wins {
+		return a.Wins > b.Wins
+	}
+	if a.Points != b.Points {
+		return a.Points > b.Points
+	}
+	return a.Seed < b.Seed
+}
+
+// swissPairs pairs entrants with similar records for the next Swiss round. Each entrant
+// plays the highest-standing opponent they have not met yet, falling back to a rematch
+// only when nobody else is left. With an odd field, the lowest-standing entrant without
+// a bye sits out and takes one.
+func swissPairs(t *Tournament) [][]string {
+	standings := append([]Entrant{}, t.Entrants...)
+	sort.SliceStable(standings, func(i, j int) bool { return swissAhead(standings[i], standings[j]) })
+
+	met := make(map[string]map[string]bool)
+	for _, round := range t.Rounds {
+		for _, match := range round.Matches {
+			for _, a := range match.Entrants {
+				for _, b := range match.Entrants {
+					if a != b {
+						if met[a] == nil {
+							met[a] = make(map[string]bool)
+						}
+						met[a][b] = true
+					}
+				}
+			}
+		}
+	}
+
+	var pairs [][]string
+	if len(standings)%2 == 1 {
+		bye := len(standings) - 1
+		for i := len(standings) - 1; i >= 0; i-- {
+			if standings[i].Byes == 0 {
+				bye = i
+				break
+			}
+		}
+		pairs = append(pairs, []string{standings[bye].ID})
+		standings = append(standings[:bye], standings[bye+1:]...)
+	}
+
+	paired := make([]bool, len(standings))
+	for i := range standings {
+		if paired[i] {
+			continue
+		}
+		opponent := -1
+		for j := i + 1; j < len(standings); j++ {
+			if paired[j] {
+				continue
+			}
+			if opponent == -1 {
+				opponent = j // Rematch fallback.
+			}
+			if !met[standings[i].ID][standings[j].ID] {
+				opponent = j
+				break
+			}
+		}
+		paired[i], paired[opponent] = true, true
+		pairs = append(pairs, []string{standings[i].ID, standings[opponent].ID})
+	}
+	return pairs
+}
@@ -0,0 +1,522 @@
+// Package tournament runs tournaments on top of the session store: it registers players,
+// pairs them into single-elimination brackets or Swiss rounds, spawns one game session per
+// match and advances winners as match results come in.
+package tournament
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+	"github.com/google/uuid"
+)
+
+// Format selects how a tournament pairs its players.
+type Format string
+
+const (
+	FormatBracket Format = "bracket" // Single elimination; the winner of each match advances.
+	FormatSwiss   Format = "swiss"   // A fixed number of rounds pairing players with similar records.
+)
+
+// Status is the stage a tournament is in.
+type Status string
+
+const (
+	StatusRegistration Status = "registration"
+	StatusRunning      Status = "running"
+	StatusComplete     Status = "complete"
+)
+
+// MatchStatus is the stage a match is in.
+type MatchStatus string
+
+const (
+	MatchPending  MatchStatus = "pending"  // Waiting for its game session to be created.
+	MatchPlaying  MatchStatus = "playing"  // Its game session is open.
+	MatchComplete MatchStatus = "complete" // Its result has been recorded.
+	MatchBye      MatchStatus = "bye"      // A lone player advanced without playing.
+)
+
+var (
+	ErrNotFound           = errors.New("tournament not found")
+	ErrUnknownFormat      = errors.New("unknown tournament format")
+	ErrRegistrationClosed = errors.New("registration is closed")
+	ErrNotEnoughPlayers   = errors.New("a tournament needs at least two players")
+	ErrNotMatchSession    = errors.New("session is not a tournament match")
+	ErrNotInMatch         = errors.New("player is not in this match")
+	ErrInvalidEntrant     = errors.New("invalid entrant token")
+	ErrSeatTaken          = errors.New("player already joined this match")
+)
+
+// SessionCreator creates the game session a match is played in. SessionStore implements it.
+type SessionCreator interface {
+	CreateSession(options models.GameOptions) (string, error)
+}
+
+// Entrant is a registered player and their record so far.
+type Entrant struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Rating     int    `json:"rating"` // Higher ratings get better seeds.
+	Seed       int    `json:"seed"`   // 1 for the top seed; set when the tournament starts.
+	Wins       int    `json:"wins"`
+	Losses     int    `json:"losses"`
+	Points     int    `json:"points"` // Total score across matches, the first Swiss tie-break.
+	Byes       int    `json:"byes"`
+	Eliminated bool   `json:"eliminated"`
+	Rank       int    `json:"rank,omitempty"` // Final or current standing; tied players share it.
+}
+
+// Match pairs entrants in one game session.
+type Match struct {
+	ID        string            `json:"id"`
+	Round     int               `json:"round"`
+	Entrants  []string          `json:"entrants"` // Entrant IDs; a single entrant is a bye.
+	SessionID string            `json:"sessionId,omitempty"`
+	Status    MatchStatus       `json:"status"`
+	Scores    map[string]int    `json:"scores"` // Entrant ID to score once complete.
+	WinnerID  string            `json:"winnerId,omitempty"`
+	claimed   map[string]bool   // Entrants who have taken their seat in the session.
+	seats     map[string]string // Session player ID to the entrant playing as that player.
+	creating  bool              // Its game session is being created.
+}
+
+// Round is one set of matches played in parallel.
+type Round struct {
+	Number  int     `json:"number"`
+	Matches []Match `json:"matches"`
+}
+
+// Tournament is the full state of a tournament.
+type Tournament struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Format      Format             `json:"format"`
+	Status      Status             `json:"status"`
+	Options     models.GameOptions `json:"options"`     // Options for every match session.
+	TotalRounds int                `json:"totalRounds"` // Planned rounds; set when the tournament starts.
+	Entrants    []Entrant          `json:"entrants"`
+	Rounds      []Round            `json:"rounds"`
+	WinnerID    string             `json:"winnerId,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	tokens      map[string]string  // Entrant ID to the secret token issued at registration.
+}
+
+// matchRef locates a match within its tournament.
+type matchRef struct {
+	tournament *Tournament
+	round      int
+	index      int
+}
+
+// Manager tracks tournaments and the sessions their matches are played in.
+type Manager struct {
+	sync.Mutex
+	Sessions    SessionCreator
+	tournaments map[string]*Tournament
+	matches     map[string]matchRef // Session ID to its match.
+}
+
+// NewManager initializes a manager that plays matches in sessions from the given creator.
+func NewManager(sessions SessionCreator) *Manager {
+	return &Manager{
+		Sessions:    sessions,
+		tournaments: make(map[string]*Tournament),
+		matches:     make(map[string]matchRef),
+	}
+}
+
+// Create opens a tournament for registration. For Swiss tournaments, rounds may be zero to
+// play enough rounds to separate the players; bracket tournaments ignore it.
+func (m *Manager) Create(name string, format Format, rounds int, options models.GameOptions) (Tournament, error) {
+	if format != FormatBracket && format != FormatSwiss {
+		return Tournament{}, ErrUnknownFormat
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	t := &Tournament{
+		ID:          uuid.New().String(),
+		Name:        name,
+		Format:      format,
+		Status:      StatusRegistration,
+		Options:     options,
+		TotalRounds: rounds,
+		Entrants:    []Entrant{},
+		Rounds:      []Round{},
+		CreatedAt:   time.Now(),
+		tokens:      make(map[string]string),
+	}
+	m.tournaments[t.ID] = t
+	return t.view(), nil
+}
+
+// Get returns a copy of a tournament's state.
+func (m *Manager) Get(id string) (Tournament, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	t, exists := m.tournaments[id]
+	if !exists {
+		return Tournament{}, ErrNotFound
+	}
+	return t.view(), nil
+}
+
+// Register adds a player to a tournament that has not started. It returns the entrant and
+// the secret token the player takes their seat in matches with; entrant IDs are public.
+func (m *Manager) Register(id, name string, rating int) (Entrant, string, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	t, exists := m.tournaments[id]
+	if !exists {
+		return Entrant{}, "", ErrNotFound
+	}
+	if t.Status != StatusRegistration {
+		return Entrant{}, "", ErrRegistrationClosed
+	}
+
+	entrant := Entrant{ID: uuid.New().String(), Name: name, Rating: rating}
+	t.Entrants = append(t.Entrants, entrant)
+	token := uuid.New().String()
+	t.tokens[entrant.ID] = token
+	return entrant, token, nil
+}
+
+// Start seeds the players and plays the first round. Calling it on a running tournament
+// retries any match whose session could not be created.
+func (m *Manager) Start(id string) error {
+	m.Lock()
+	pending, err := m.start(id)
+	m.Unlock()
+
+	m.spawn(pending)
+	return err
+}
+
+// start is Start up to creating the sessions, returning the matches that need one. The
+// caller must hold the lock.
+func (m *Manager) start(id string) ([]matchRef, error) {
+	t, exists := m.tournaments[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	if t.Status == StatusRunning {
+		return m.pending(t), nil
+	}
+	if t.Status != StatusRegistration {
+		return nil, ErrRegistrationClosed
+	}
+	if len(t.Entrants) < 2 {
+		return nil, ErrNotEnoughPlayers
+	}
+
+	// Seed by rating, keeping registration order among equal ratings.
+	sort.SliceStable(t.Entrants, func(i, j int) bool { return t.Entrants[i].Rating > t.Entrants[j].Rating })
+	for i := range t.Entrants {
+		t.Entrants[i].Seed = i + 1
+	}
+
+	t.Status = StatusRunning
+	switch t.Format {
+	case FormatBracket:
+		size := bracketSize(len(t.Entrants))
+		t.TotalRounds = 0
+		for remaining := size; remaining > 1; remaining /= 2 {
+			t.TotalRounds++
+		}
+		m.addRound(t, bracketPairs(t, size))
+	case FormatSwiss:
+		if t.TotalRounds <= 0 {
+			for remaining := 1; remaining < len(t.Entrants); remaining *= 2 {
+				t.TotalRounds++
+			}
+		}
+		m.addRound(t, swissPairs(t))
+	}
+	m.advance(t)
+	return m.pending(t), nil
+}
+
+// Seat claims an entrant's place in the match played in a session, returning the entrant's
+// name. The token issued at registration proves the caller is the entrant. It returns
+// ErrNotMatchSession for sessions that are not tournament matches.
+func (m *Manager) Seat(sessionID, entrantID, token string) (string, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	ref, exists := m.matches[sessionID]
+	if !exists {
+		return "", ErrNotMatchSession
+	}
+	match := ref.match()
+	if !contains(match.Entrants, entrantID) {
+		return "", ErrNotInMatch
+	}
+	issued := ref.tournament.tokens[entrantID]
+	if issued == "" || subtle.ConstantTimeCompare([]byte(token), []byte(issued)) != 1 {
+		return "", ErrInvalidEntrant
+	}
+	if match.claimed[entrantID] {
+		return "", ErrSeatTaken
+	}
+
+	match.claimed[entrantID] = true
+	return ref.tournament.entrant(entrantID).Name, nil
+}
+
+// Bind records which session player an entrant seated with Seat plays as.
+func (m *Manager) Bind(sessionID, entrantID, playerID string) {
+	m.Lock()
+	defer m.Unlock()
+
+	if ref, exists := m.matches[sessionID]; exists {
+		ref.match().seats[playerID] = entrantID
+	}
+}
+
+// RecordResult settles the match played in a session from its players' final scores, keyed
+// by session player ID. The higher score wins; a tie goes to the better seed. Once every
+// match of a round is settled, the next round is paired and its sessions created.
+// Results for sessions that are not matches, or already settled, are ignored.
+func (m *Manager) RecordResult(sessionID string, scores map[string]int) {
+	m.Lock()
+	pending := m.recordResult(sessionID, scores)
+	m.Unlock()
+
+	m.spawn(pending)
+}
+
+// recordResult is RecordResult up to creating the next round's sessions, returning the
+// matches that need one. The caller must hold the lock.
+func (m *Manager) recordResult(sessionID string, scores map[string]int) []matchRef {
+	ref, exists := m.matches[sessionID]
+	if !exists {
+		return nil
+	}
+	match := ref.match()
+	if match.Status != MatchPlaying {
+		return nil
+	}
+
+	t := ref.tournament
+	for playerID, score := range scores {
+		if entrantID, seated := match.seats[playerID]; seated {
+			match.Scores[entrantID] = score
+		}
+	}
+
+	winner := ""
+	for _, entrantID := range match.Entrants {
+		if winner == "" || match.Scores[entrantID] > match.Scores[winner] ||
+			(match.Scores[entrantID] == match.Scores[winner] && t.entrant(entrantID).Seed < t.entrant(winner).Seed) {
+			winner = entrantID
+		}
+	}
+	match.WinnerID = winner
+	match.Status = MatchComplete
+
+	for _, entrantID := range match.Entrants {
+		entrant := t.entrant(entrantID)
+		entrant.Points += match.Scores[entrantID]
+		if entrantID == winner {
+			entrant.Wins++
+			continue
+		}
+		entrant.Losses++
+		if t.Format == FormatBracket {
+			entrant.Eliminated = true
+			entrant.Rank = len(t.Rounds[match.Round-1].Matches) + 1
+		}
+	}
+	log.Printf("Tournament %s match %s won by %s", t.ID, match.ID, t.entrant(winner).Name)
+
+	m.advance(t)
+	return m.pending(t)
+}
+
+// advance pairs the next round once the current one is settled, or finishes the
+// tournament after its last round. The caller must hold the lock.
+func (m *Manager) advance(t *Tournament) {
+	for t.Status == StatusRunning && t.roundComplete() {
+		current := t.Rounds[len(t.Rounds)-1]
+		if t.Format == FormatSwiss {
+			t.rankSwiss()
+		}
+		if current.Number >= t.TotalRounds {
+			m.finish(t)
+			return
+		}
+
+		switch t.Format {
+		case FormatBracket:
+			var winners []string
+			for _, match := range current.Matches {
+				winners = append(winners, match.WinnerID)
+			}
+			var pairs [][]string
+			for i := 0; i+1 < len(winners); i += 2 {
+				pairs = append(pairs, []string{winners[i], winners[i+1]})
+			}
+			m.addRound(t, pairs)
+		case FormatSwiss:
+			m.addRound(t, swissPairs(t))
+		}
+	}
+}
+
+// finish closes a tournament and records its winner. The caller must hold the lock.
+func (m *Manager) finish(t *Tournament) {
+	t.Status = StatusComplete
+	switch t.Format {
+	case FormatBracket:
+		final := t.Rounds[len(t.Rounds)-1].Matches[0]
+		t.WinnerID = final.WinnerID
+		t.entrant(final.WinnerID).Rank = 1
+	case FormatSwiss:
+		for _, entrant := range t.Entrants {
+			if entrant.Rank == 1 {
+				t.WinnerID = entrant.ID
+				break
+			}
+		}
+	}
+	log.Printf("Tournament %s complete", t.ID)
+}
+
+// addRound appends a round of matches for the given pairs, settling byes straight away and
+// leaving the rest pending until their sessions are created. The caller must hold the lock.
+func (m *Manager) addRound(t *Tournament, pairs [][]string) {
+	round := Round{Number: len(t.Rounds) + 1}
+	for i, entrants := range pairs {
+		match := Match{
+			ID:       fmt.Sprintf("r%d-m%d", round.Number, i+1),
+			Round:    round.Number,
+			Entrants: entrants,
+			Status:   MatchPending,
+			Scores:   make(map[string]int),
+			claimed:  make(map[string]bool),
+			seats:    make(map[string]string),
+		}
+		if len(entrants) == 1 {
+			match.Status = MatchBye
+			match.WinnerID = entrants[0]
+			entrant := t.entrant(entrants[0])
+			entrant.Wins++
+			entrant.Byes++
+		}
+		round.Matches = append(round.Matches, match)
+	}
+	t.Rounds = append(t.Rounds, round)
+}
+
+// pending claims the current round's matches that have no session and none being created,
+// for spawn to create them. The caller must hold the lock.
+func (m *Manager) pending(t *Tournament) []matchRef {
+	if len(t.Rounds) == 0 {
+		return nil
+	}
+	var refs []matchRef
+	roundIndex := len(t.Rounds) - 1
+	for i := range t.Rounds[roundIndex].Matches {
+		match := &t.Rounds[roundIndex].Matches[i]
+		if match.Status != MatchPending || match.creating {
+			continue
+		}
+		match.creating = true
+		refs = append(refs, matchRef{tournament: t, round: roundIndex, index: i})
+	}
+	return refs
+}
+
+// spawn creates the sessions for matches claimed by pending. Creating a session may fetch
+// questions over the network, so it runs without the lock, which is taken again to attach
+// each session. Failures are logged and left pending for a retry.
+func (m *Manager) spawn(refs []matchRef) {
+	for _, ref := range refs {
+		m.Lock()
+		options := ref.tournament.Options
+		m.Unlock()
+
+		sessionID, err := m.Sessions.CreateSession(options)
+
+		m.Lock()
+		match := ref.match()
+		match.creating = false
+		if err != nil {
+			log.Printf("Failed to create session for tournament %s match %s: %v", ref.tournament.ID, match.ID, err)
+		} else {
+			match.SessionID = sessionID
+			match.Status = MatchPlaying
+			m.matches[sessionID] = ref
+		}
+		m.Unlock()
+	}
+}
+
+// match returns the referenced match.
+func (ref matchRef) match() *Match {
+	return &ref.tournament.Rounds[ref.round].Matches[ref.index]
+}
+
+// entrant looks up an entrant by ID.
+func (t *Tournament) entrant(id string) *Entrant {
+	for i := range t.Entrants {
+		if t.Entrants[i].ID == id {
+			return &t.Entrants[i]
+		}
+	}
+	return &Entrant{}
+}
+
+// roundComplete reports whether every match of the current round is settled.
+func (t *Tournament) roundComplete() bool {
+	if len(t.Rounds) == 0 {
+		return false
+	}
+	for _, match := range t.Rounds[len(t.Rounds)-1].Matches {
+		if match.Status != MatchComplete && match.Status != MatchBye {
+			return false
+		}
+	}
+	return true
+}
+
+// view returns a deep copy of the tournament that is safe to use without the lock.
+func (t *Tournament) view() Tournament {
+	view := *t
+	view.Entrants = append([]Entrant{}, t.Entrants...)
+	view.Rounds = make([]Round, len(t.Rounds))
+	for i, round := range t.Rounds {
+		view.Rounds[i] = Round{Number: round.Number, Matches: make([]Match, len(round.Matches))}
+		for j, match := range round.Matches {
+			match.Entrants = append([]string{}, match.Entrants...)
+			scores := make(map[string]int, len(match.Scores))
+			for entrantID, score := range match.Scores {
+				scores[entrantID] = score
+			}
+			match.Scores = scores
+			match.claimed, match.seats, match.creating = nil, nil, false
+			view.Rounds[i].Matches[j] = match
+		}
+	}
+	return view
+}
+
+// contains reports whether ids includes id.
+func contains(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
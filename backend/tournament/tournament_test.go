@@ -0,0 +1,125 @@
+package tournament
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gclluch/TriviaApp-ReactGo/models"
+)
+
+// fakeSessions hands out numbered session IDs.
+type fakeSessions struct{ created int }
+
+func (f *fakeSessions) CreateSession(options models.GameOptions) (string, error) {
+	f.created++
+	return fmt.Sprintf("session-%d", f.created), nil
+}
+
+// register adds players to a tournament, returning their entrant tokens by entrant ID.
+func register(m *Manager, id string, ratings map[string]int) map[string]string {
+	tokens := make(map[string]string)
+	for name, rating := range ratings {
+		entrant, token, _ := m.Register(id, name, rating)
+		tokens[entrant.ID] = token
+	}
+	return tokens
+}
+
+// play seats both entrants of a match and records the given scores, in entrant order.
+func play(t *testing.T, m *Manager, tokens map[string]string, match Match, scores ...int) {
+	t.Helper()
+	results := make(map[string]int)
+	for i, entrantID := range match.Entrants {
+		if _, err := m.Seat(match.SessionID, entrantID, tokens[entrantID]); err != nil {
+			t.Fatalf("Seat failed: %v", err)
+		}
+		m.Bind(match.SessionID, entrantID, "player-"+entrantID)
+		results["player-"+entrantID] = scores[i]
+	}
+	m.RecordResult(match.SessionID, results)
+}
+
+func TestBracketSeedsByesAndAdvancesWinners(t *testing.T) {
+	sessions := &fakeSessions{}
+	m := NewManager(sessions)
+	created, _ := m.Create("Monthly", FormatBracket, 0, models.GameOptions{NumQuestions: 5})
+	tokens := register(m, created.ID, map[string]int{"Low": 0, "Top": 101, "Mid": 2})
+	if err := m.Start(created.ID); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	state, _ := m.Get(created.ID)
+	first := state.Rounds[0].Matches
+	if len(first) != 2 || first[0].Status != MatchBye || sessions.created != 1 {
+		t.Fatalf("The top seed should get a bye and one session should be created: %+v", first)
+	}
+	bye := first[0].Entrants[0]
+	if _, err := m.Seat(first[1].SessionID, bye, tokens[bye]); err != ErrNotInMatch {
+		t.Errorf("Expected ErrNotInMatch for an entrant from another match; got %v", err)
+	}
+	seated := first[1].Entrants[0]
+	if _, err := m.Seat(first[1].SessionID, seated, tokens[bye]); err != ErrInvalidEntrant {
+		t.Errorf("Expected ErrInvalidEntrant for another entrant's token; got %v", err)
+	}
+
+	// A tie goes to the better seed.
+	play(t, m, tokens, first[1], 30, 30)
+	state, _ = m.Get(created.ID)
+	if winner := state.Rounds[0].Matches[1].WinnerID; entrantByID(state, winner).Seed != 2 {
+		t.Errorf("Expected seed 2 to win the tie; got seed %d", entrantByID(state, winner).Seed)
+	}
+	if len(state.Rounds) != 2 {
+		t.Fatalf("The final should be paired once the first round is settled")
+	}
+
+	final := state.Rounds[1].Matches[0]
+	play(t, m, tokens, final, 10, 20)
+	state, _ = m.Get(created.ID)
+	if state.Status != StatusComplete || state.WinnerID != final.Entrants[1] {
+		t.Errorf("Expected the final's winner to win the tournament: %+v", state)
+	}
+	if entrantByID(state, final.Entrants[0]).Rank != 2 {
+		t.Errorf("The losing finalist should rank second")
+	}
+}
+
+func TestSwissAvoidsRematches(t *testing.T) {
+	m := NewManager(&fakeSessions{})
+	created, _ := m.Create("Swiss", FormatSwiss, 0, models.GameOptions{NumQuestions: 5})
+	tokens := register(m, created.ID, map[string]int{"A": 0, "B": 0, "C": 0, "D": 0})
+	m.Start(created.ID)
+
+	state, _ := m.Get(created.ID)
+	if state.TotalRounds != 2 {
+		t.Fatalf("Four players should play two Swiss rounds; got %d", state.TotalRounds)
+	}
+	for _, match := range state.Rounds[0].Matches {
+		play(t, m, tokens, match, 20, 10)
+	}
+
+	state, _ = m.Get(created.ID)
+	met := make(map[string]string)
+	for _, match := range state.Rounds[0].Matches {
+		met[match.Entrants[0]], met[match.Entrants[1]] = match.Entrants[1], match.Entrants[0]
+	}
+	for _, match := range state.Rounds[1].Matches {
+		if met[match.Entrants[0]] == match.Entrants[1] {
+			t.Errorf("Round two paired a rematch: %v", match.Entrants)
+		}
+		play(t, m, tokens, match, 20, 10)
+	}
+
+	state, _ = m.Get(created.ID)
+	if state.Status != StatusComplete || entrantByID(state, state.WinnerID).Wins != 2 {
+		t.Errorf("The only undefeated player should win: %+v", state)
+	}
+}
+
+func entrantByID(state Tournament, id string) Entrant {
+	for _, entrant := range state.Entrants {
+		if entrant.ID == id {
+			return entrant
+		}
+	}
+	return Entrant{}
+}
@@ -9,6 +9,7 @@ interface RouteParams {
 interface JoinGameData {
   playerName: string;
   playerId: string;
+  resumeToken: string;
 }
 
 const API_BASE = process.env.REACT_APP_BACKEND_URL || 'http://localhost:8080';
@@ -22,6 +23,7 @@ const JoinGameComponent: React.FC = () => {
   const [hasJoined, setHasJoined] = useState<boolean>(false);
   const [playerName, setPlayerName] = useState<string>('');
   const [playerId, setPlayerId] = useState<string>('');
+  const [resumeToken, setResumeToken] = useState<string>('');
   const [playerCount, setPlayerCount] = useState<number>(0);
   const [countdown, setCountdown] = useState<number | null>(null);
 
@@ -38,6 +40,7 @@ const JoinGameComponent: React.FC = () => {
       setHasJoined(true);
       setPlayerName(data.playerName);
       setPlayerId(data.playerId);
+      setResumeToken(data.resumeToken);
     } catch (error) {
       console.error('Failed to join the game:', error);
     }
@@ -78,15 +81,24 @@ const JoinGameComponent: React.FC = () => {
     }
   }, [webSocket, isConnected, sessionId]);
 
+  // Once joined, bind the connection to this player so their events are not delayed like a spectator's.
+  useEffect(() => {
+    if (webSocket && isConnected && resumeToken) {
+      webSocket.send(
+        JSON.stringify({ action: 'joinSession', sessionId, resumeToken }),
+      );
+    }
+  }, [webSocket, isConnected, sessionId, resumeToken]);
+
   useEffect(() => {
     if (countdown === 0 && hasJoined) {
       navigate(`/game/${sessionId}`, {
-        state: { playerName, playerId, gameStarted: true },
+        state: { playerName, playerId, resumeToken, gameStarted: true },
       });
     } else if (countdown === 0) {
       navigate(`/game/${sessionId}`, { state: { gameStarted: false } });
     }
-  }, [countdown, navigate, hasJoined, playerName, sessionId, playerId]);
+  }, [countdown, navigate, hasJoined, playerName, sessionId, playerId, resumeToken]);
 
   return (
     <div>
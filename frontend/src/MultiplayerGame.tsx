@@ -8,6 +8,7 @@ import { useWebSocket } from './WebSocketContext';
 interface LocationState {
   playerName: string;
   playerId: string;
+  resumeToken: string;
   gameStarted: boolean;
 }
 
@@ -25,9 +26,10 @@ const MultiplayerGame: React.FC = () => {
   const location = useLocation();
 
   // Type assertion for location.state
-  const { playerName, playerId, gameStarted } = (location.state || {
+  const { playerName, playerId, resumeToken, gameStarted } = (location.state || {
     playerName: '',
     playerId: '',
+    resumeToken: '',
     gameStarted: false,
   }) as LocationState;
 
@@ -89,7 +91,7 @@ const MultiplayerGame: React.FC = () => {
         headers: { 'Content-Type': 'application/json' },
         body: JSON.stringify({
           sessionId,
-          playerId,
+          resumeToken,
           questionId: currentQuestion.id,
           answer: index,
         }),
@@ -116,7 +118,7 @@ const MultiplayerGame: React.FC = () => {
       await fetch(`${API_BASE}/player/finished`, {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ sessionId, playerId }),
+        body: JSON.stringify({ sessionId, resumeToken }),
       });
       setHasFinished(true);
     } catch (error) {